@@ -0,0 +1,54 @@
+package go_mermaid_gantt
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pyroflux/go-mermaid-gantt/internal/parser"
+)
+
+// Variance 为任务相对基线的偏差（按任务 ID 匹配），天数按工作日计，正值表示推迟。
+type Variance struct {
+	TaskID        string
+	Name          string
+	BaselineStart time.Time
+	BaselineEnd   time.Time
+	Start         time.Time
+	End           time.Time
+	StartDays     int  // 开始偏差
+	FinishDays    int  // 完成偏差
+	Slipped       bool // 完成晚于基线
+	Added         bool // 基线中不存在的任务
+	Removed       bool // 仅存在于基线中的任务
+}
+
+// applyBaseline 将 Input.BaselinePlan 或 Input.Baseline 指定的基线写入已排程模型，返回偏差；未指定基线时返回 nil。
+func applyBaseline(model *parser.Model, in Input) ([]Variance, error) {
+	var tasks []parser.BaselineTask
+	switch {
+	case in.BaselinePlan != nil:
+		for _, t := range in.BaselinePlan.Tasks {
+			tasks = append(tasks, parser.BaselineTask{ID: t.ID, Name: t.Name, Start: t.Start, End: t.End})
+		}
+	case in.Baseline != "":
+		base, err := loadModel(Input{
+			Source:             in.Baseline,
+			CalendarFS:         in.CalendarFS,
+			AllowLocalCalendar: in.AllowLocalCalendar,
+			Timezone:           in.Timezone,
+			Today:              in.Today,
+			LevelResources:     in.LevelResources,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("baseline: %w", err)
+		}
+		tasks = parser.BaselineOf(base)
+	default:
+		return nil, nil
+	}
+	var out []Variance
+	for _, v := range parser.ApplyBaseline(model, tasks) {
+		out = append(out, Variance(v))
+	}
+	return out, nil
+}
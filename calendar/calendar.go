@@ -0,0 +1,230 @@
+package calendar
+
+import "time"
+
+const (
+	// maxSkippedDays 限制连续跳过的非工作日数，防止日历配置为全周休息时死循环。
+	maxSkippedDays = 3660
+	hoursPerDay    = 24
+	minutesPerHour = 60
+)
+
+// Calendar 记录日历规则。
+type Calendar struct {
+	Timezone       string
+	ExcludeWeekend bool
+	WeekendDays    []time.Weekday // 可自定义周末集合；为空时使用默认周末
+	ExcludeDates   []time.Time
+	IncludeDates   []time.Time
+	WorkHours      WorkHours
+	Holidays       []Holiday // 带名称的假日与调休上班日，来自 holidays 指令或 ics 日历
+}
+
+// ClockRange 为一天内的工作时段，以自零点起的分钟数表示，[Start, End)。
+type ClockRange struct {
+	Start int
+	End   int
+}
+
+// WorkHours 描述每日工作时段：Default 适用于所有工作日，ByWeekday 按星期覆盖。
+// 未配置时按全天计时。
+type WorkHours struct {
+	Default   []ClockRange
+	ByWeekday map[time.Weekday][]ClockRange // 按星期覆盖，空切片表示当日不工作
+}
+
+// Holiday 为带名称的排除日或调休上班日，用于标注。
+type Holiday struct {
+	Date    time.Time
+	Name    string
+	Workday bool // true 表示调休上班日（已加入 IncludeDates）
+}
+
+// Location 返回 Timezone 对应的时区，未设置或无效时为 UTC。
+func (c Calendar) Location() *time.Location {
+	if c.Timezone != "" {
+		if tz, err := time.LoadLocation(c.Timezone); err == nil {
+			return tz
+		}
+	}
+	return time.UTC
+}
+
+// IsWeekend 判断 t 所在日是否属于周末集合（不考虑 ExcludeWeekend 与 includes）。
+func (c Calendar) IsWeekend(t time.Time) bool {
+	if len(c.WeekendDays) == 0 {
+		return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+	}
+	for _, wd := range c.WeekendDays {
+		if t.Weekday() == wd {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWorkingDay 判断 t 所在日（按 t 自身的时区取日期）是否为工作日：
+// 排除的周末可被 IncludeDates 重新纳入，ExcludeDates 中的日期不工作。
+func (c Calendar) IsWorkingDay(t time.Time) bool {
+	if c.ExcludeWeekend && c.IsWeekend(t) {
+		return containsDay(c.IncludeDates, t)
+	}
+	return !containsDay(c.ExcludeDates, t)
+}
+
+// NextWorkingDay 返回 t 本身（t 所在日为工作日时）或其后首个工作日的零点。
+func (c Calendar) NextWorkingDay(t time.Time) time.Time {
+	for i := 0; i < maxSkippedDays && !c.IsWorkingDay(t); i++ {
+		t = startOfNextDay(t)
+	}
+	return t
+}
+
+// AddWorkingDays 从 t 起移动 n 个工作日：n>0 时越过随后的 n 个工作日，n<0 时向前回退到第 |n| 个工作日。
+// 时刻保持不变。
+func (c Calendar) AddWorkingDays(t time.Time, n int) time.Time {
+	for ; n > 0; n-- {
+		for i := 0; i < maxSkippedDays && !c.IsWorkingDay(t); i++ {
+			t = t.AddDate(0, 0, 1)
+		}
+		t = t.AddDate(0, 0, 1)
+	}
+	for ; n < 0; n++ {
+		t = t.AddDate(0, 0, -1)
+		for i := 0; i < maxSkippedDays && !c.IsWorkingDay(t); i++ {
+			t = t.AddDate(0, 0, -1)
+		}
+	}
+	return t
+}
+
+// WorkingDaysBetween 统计 [from, to) 之间的工作日数（按日计），to 早于 from 时返回负值。
+func (c Calendar) WorkingDaysBetween(from, to time.Time) int {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, from.Location())
+	sign := 1
+	if to.Before(from) {
+		from, to = to, from
+		sign = -1
+	}
+	n := 0
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		if c.IsWorkingDay(d) {
+			n++
+		}
+	}
+	return sign * n
+}
+
+// AddWorkingDuration 从 start 起消耗 d 的工作时间，返回结束时刻：非工作日整天跳过；
+// 配置了工作时段时只在时段内计时，跨越下班时间顺延到下一个工作时刻。
+func (c Calendar) AddWorkingDuration(start time.Time, d time.Duration) time.Time {
+	if c.WorkHours.Enabled() {
+		return c.addWorkHours(start, d)
+	}
+	cur := start
+	for skipped := 0; d > 0 && skipped < maxSkippedDays; {
+		if !c.IsWorkingDay(cur) {
+			cur = startOfNextDay(cur)
+			skipped++
+			continue
+		}
+		skipped = 0
+		next := startOfNextDay(cur)
+		if span := next.Sub(cur); d > span {
+			d -= span
+			cur = next
+			continue
+		}
+		return cur.Add(d)
+	}
+	return cur
+}
+
+// NextWorkingTime 返回 t 之后（含）最近的工作时刻，跳过非工作日与工作时段之外的时间；
+// 未配置工作时段时等同于 NextWorkingDay。
+func (c Calendar) NextWorkingTime(t time.Time) time.Time {
+	if !c.WorkHours.Enabled() {
+		return c.NextWorkingDay(t)
+	}
+	cur := t
+	for i := 0; i < maxSkippedDays; i++ {
+		day := time.Date(cur.Year(), cur.Month(), cur.Day(), 0, 0, 0, 0, cur.Location())
+		if c.IsWorkingDay(day) {
+			for _, r := range c.WorkHours.For(day.Weekday()) {
+				rs, re := r.Bounds(day)
+				if cur.Before(re) {
+					if cur.Before(rs) {
+						return rs
+					}
+					return cur
+				}
+			}
+		}
+		cur = day.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// addWorkHours 只在工作时段内消耗 d。
+func (c Calendar) addWorkHours(start time.Time, d time.Duration) time.Time {
+	cur := c.NextWorkingTime(start)
+	for i := 0; i < maxSkippedDays && d > 0; i++ {
+		day := time.Date(cur.Year(), cur.Month(), cur.Day(), 0, 0, 0, 0, cur.Location())
+		if c.IsWorkingDay(day) {
+			for _, r := range c.WorkHours.For(day.Weekday()) {
+				rs, re := r.Bounds(day)
+				if !cur.Before(re) {
+					continue
+				}
+				if cur.Before(rs) {
+					cur = rs
+				}
+				avail := re.Sub(cur)
+				if d <= avail {
+					return cur.Add(d)
+				}
+				d -= avail
+				cur = re
+			}
+		}
+		cur = day.AddDate(0, 0, 1)
+	}
+	return cur
+}
+
+// Enabled 表示是否配置了工作时段。
+func (w WorkHours) Enabled() bool {
+	return len(w.Default) > 0 || len(w.ByWeekday) > 0
+}
+
+// For 返回某个星期几的工作时段；未配置时为全天。
+func (w WorkHours) For(wd time.Weekday) []ClockRange {
+	if r, ok := w.ByWeekday[wd]; ok {
+		return r
+	}
+	if len(w.Default) > 0 {
+		return w.Default
+	}
+	return []ClockRange{{Start: 0, End: hoursPerDay * minutesPerHour}}
+}
+
+// Bounds 返回时段在 day 当天的起止时刻。
+func (r ClockRange) Bounds(day time.Time) (time.Time, time.Time) {
+	return day.Add(time.Duration(r.Start) * time.Minute), day.Add(time.Duration(r.End) * time.Minute)
+}
+
+func startOfNextDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location()).AddDate(0, 0, 1)
+}
+
+func containsDay(list []time.Time, t time.Time) bool {
+	ty, tm, td := t.Date()
+	for _, d := range list {
+		if y, m, dd := d.Date(); y == ty && m == tm && dd == td {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestCalendar_WorkingDays(t *testing.T) {
+	cal := Calendar{
+		ExcludeWeekend: true,
+		WeekendDays:    []time.Weekday{time.Friday, time.Saturday},
+		ExcludeDates:   []time.Time{day("2025-03-04")},
+		IncludeDates:   []time.Time{day("2025-03-08")},
+	}
+	if cal.IsWorkingDay(day("2025-03-07")) {
+		t.Fatalf("friday should be a weekend day")
+	}
+	if !cal.IsWorkingDay(day("2025-03-09")) {
+		t.Fatalf("sunday is a working day with a fri/sat weekend")
+	}
+	if !cal.IsWorkingDay(day("2025-03-08")) {
+		t.Fatalf("included saturday should be a working day")
+	}
+	if got := cal.NextWorkingDay(day("2025-03-04").Add(10 * time.Hour)); !got.Equal(day("2025-03-05")) {
+		t.Fatalf("expected next working day 2025-03-05, got %s", got)
+	}
+	// 03-03..03-09：排除 03-04 与 03-07
+	if got := cal.WorkingDaysBetween(day("2025-03-03"), day("2025-03-10")); got != 5 {
+		t.Fatalf("expected 5 working days, got %d", got)
+	}
+	if got := cal.WorkingDaysBetween(day("2025-03-10"), day("2025-03-03")); got != -5 {
+		t.Fatalf("expected -5 working days, got %d", got)
+	}
+	if got := cal.AddWorkingDays(day("2025-03-03"), 2); !got.Equal(day("2025-03-06")) {
+		t.Fatalf("expected 2025-03-06, got %s", got)
+	}
+	if got := cal.AddWorkingDuration(day("2025-03-03"), 3*24*time.Hour); !got.Equal(day("2025-03-07")) {
+		t.Fatalf("expected 3 working days to end at 2025-03-07, got %s", got)
+	}
+}
+
+func TestCalendar_WorkHours(t *testing.T) {
+	cal := Calendar{
+		ExcludeWeekend: true,
+		WorkHours:      WorkHours{Default: []ClockRange{{Start: 9 * 60, End: 12 * 60}, {Start: 13 * 60, End: 18 * 60}}},
+	}
+	fri := time.Date(2025, 3, 7, 16, 0, 0, 0, time.UTC)
+	if got := cal.AddWorkingDuration(fri, 4*time.Hour); got.Format("2006-01-02 15:04") != "2025-03-10 11:00" {
+		t.Fatalf("expected 2025-03-10 11:00, got %s", got.Format("2006-01-02 15:04"))
+	}
+	if got := cal.NextWorkingTime(time.Date(2025, 3, 10, 12, 30, 0, 0, time.UTC)); got.Format("15:04") != "13:00" {
+		t.Fatalf("expected lunch break to roll to 13:00, got %s", got.Format("15:04"))
+	}
+}
@@ -0,0 +1,275 @@
+package go_mermaid_gantt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pyroflux/go-mermaid-gantt/internal/parser"
+)
+
+// DiffKind 表示任务在两版排程间的变化类别。
+type DiffKind string
+
+const (
+	DiffAdded   DiffKind = "added"
+	DiffRemoved DiffKind = "removed"
+	DiffChanged DiffKind = "changed"
+)
+
+// DateChange 为起止日期的变化，Days 为新旧日期相差的工作日（按新版日历，正值表示推迟）。
+// 新增或移除的任务只有一侧日期。
+type DateChange struct {
+	Old  time.Time `json:"old,omitzero"`
+	New  time.Time `json:"new,omitzero"`
+	Days int       `json:"days"`
+}
+
+// TextChange 为名称、工期或状态等文本属性的变化。
+type TextChange struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// ProgressChange 为进度百分比的变化。
+type ProgressChange struct {
+	Old int `json:"old"`
+	New int `json:"new"`
+}
+
+// TaskDiff 描述同一 ID 任务在两版排程间的差异，未变化的属性为 nil 或空。
+type TaskDiff struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Kind        DiffKind        `json:"kind"`
+	Rename      *TextChange     `json:"rename,omitempty"`
+	Start       *DateChange     `json:"start,omitempty"`
+	End         *DateChange     `json:"end,omitempty"`
+	Duration    *TextChange     `json:"duration,omitempty"`
+	Status      *TextChange     `json:"status,omitempty"`
+	Progress    *ProgressChange `json:"progress,omitempty"`
+	AddedDeps   []string        `json:"added_deps,omitempty"`   // 新增的依赖，如 "after a1 +2d"
+	RemovedDeps []string        `json:"removed_deps,omitempty"` // 移除的依赖
+}
+
+// MilestoneSlip 为里程碑日期的移动，Days 为工作日，正值表示推迟。
+type MilestoneSlip struct {
+	ID   string    `json:"id"`
+	Name string    `json:"name"`
+	Old  time.Time `json:"old"`
+	New  time.Time `json:"new"`
+	Days int       `json:"days"`
+}
+
+// ScheduleDiff 为两版图表在排程层面的差异。
+type ScheduleDiff struct {
+	Tasks      []TaskDiff      `json:"tasks"`      // 新版顺序在前，移除的任务排在最后
+	Milestones []MilestoneSlip `json:"milestones"` // 两版均存在且日期变化的里程碑
+}
+
+// Diff 分别解析并排程 oldIn 与 newIn，按任务 ID 比较：新增/移除/改名、起止与工期、依赖、状态与进度，
+// 以及里程碑的移动天数。未写显式 ID 的任务以行号生成 ID，改动行序后可能被视为移除再新增。
+func Diff(ctx context.Context, oldIn, newIn Input) (ScheduleDiff, error) {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return ScheduleDiff{}, err
+		}
+	}
+	before, err := loadModel(oldIn)
+	if err != nil {
+		return ScheduleDiff{}, fmt.Errorf("old: %w", err)
+	}
+	after, err := loadModel(newIn)
+	if err != nil {
+		return ScheduleDiff{}, fmt.Errorf("new: %w", err)
+	}
+	return diffModels(before, after), nil
+}
+
+// Empty 表示两版排程没有差异。
+func (d ScheduleDiff) Empty() bool {
+	return len(d.Tasks) == 0 && len(d.Milestones) == 0
+}
+
+// JSON 以缩进 JSON 输出差异。
+func (d ScheduleDiff) JSON() ([]byte, error) {
+	if d.Tasks == nil {
+		d.Tasks = []TaskDiff{}
+	}
+	if d.Milestones == nil {
+		d.Milestones = []MilestoneSlip{}
+	}
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// String 以便于评审阅读的文本输出差异：`+` 新增、`-` 移除、`~` 变化，最后列出里程碑移动。
+func (d ScheduleDiff) String() string {
+	if d.Empty() {
+		return "no schedule changes\n"
+	}
+	var b strings.Builder
+	for _, t := range d.Tasks {
+		switch t.Kind {
+		case DiffAdded:
+			fmt.Fprintf(&b, "+ %s %q %s - %s\n", t.ID, t.Name, diffTime(t.Start.New), diffTime(t.End.New))
+			continue
+		case DiffRemoved:
+			fmt.Fprintf(&b, "- %s %q\n", t.ID, t.Name)
+			continue
+		}
+		fmt.Fprintf(&b, "~ %s %q\n", t.ID, t.Name)
+		if t.Rename != nil {
+			fmt.Fprintf(&b, "    renamed from %q\n", t.Rename.Old)
+		}
+		if t.Start != nil {
+			fmt.Fprintf(&b, "    start %s -> %s (%+dd)\n", diffTime(t.Start.Old), diffTime(t.Start.New), t.Start.Days)
+		}
+		if t.End != nil {
+			fmt.Fprintf(&b, "    end %s -> %s (%+dd)\n", diffTime(t.End.Old), diffTime(t.End.New), t.End.Days)
+		}
+		if t.Duration != nil {
+			fmt.Fprintf(&b, "    duration %s -> %s\n", t.Duration.Old, t.Duration.New)
+		}
+		for _, dep := range t.AddedDeps {
+			fmt.Fprintf(&b, "    + %s\n", dep)
+		}
+		for _, dep := range t.RemovedDeps {
+			fmt.Fprintf(&b, "    - %s\n", dep)
+		}
+		if t.Status != nil {
+			fmt.Fprintf(&b, "    status %s -> %s\n", t.Status.Old, t.Status.New)
+		}
+		if t.Progress != nil {
+			fmt.Fprintf(&b, "    progress %d%% -> %d%%\n", t.Progress.Old, t.Progress.New)
+		}
+	}
+	if len(d.Milestones) > 0 {
+		b.WriteString("milestones:\n")
+		for _, m := range d.Milestones {
+			fmt.Fprintf(&b, "    %s %q %s -> %s (%+dd)\n", m.ID, m.Name, diffTime(m.Old), diffTime(m.New), m.Days)
+		}
+	}
+	return b.String()
+}
+
+func diffModels(before, after parser.Model) ScheduleDiff {
+	cal := after.Calendar
+	loc := cal.Location()
+	days := func(from, to time.Time) int {
+		return cal.WorkingDaysBetween(from.In(loc), to.In(loc))
+	}
+	oldTasks := make(map[string]parser.Task)
+	for _, t := range diffTasks(before) {
+		oldTasks[t.ID] = t
+	}
+	var out ScheduleDiff
+	seen := make(map[string]bool)
+	for _, nt := range diffTasks(after) {
+		ot, ok := oldTasks[nt.ID]
+		if !ok {
+			out.Tasks = append(out.Tasks, TaskDiff{
+				ID:    nt.ID,
+				Name:  nt.Name,
+				Kind:  DiffAdded,
+				Start: &DateChange{New: nt.Start},
+				End:   &DateChange{New: nt.End},
+			})
+			continue
+		}
+		seen[nt.ID] = true
+		td := TaskDiff{ID: nt.ID, Name: nt.Name, Kind: DiffChanged}
+		if ot.Name != nt.Name {
+			td.Rename = &TextChange{Old: ot.Name, New: nt.Name}
+		}
+		if !ot.Start.Equal(nt.Start) {
+			td.Start = &DateChange{Old: ot.Start, New: nt.Start, Days: days(ot.Start, nt.Start)}
+		}
+		milestone := isMilestoneTask(ot) && isMilestoneTask(nt)
+		if !ot.End.Equal(nt.End) && !milestone {
+			td.End = &DateChange{Old: ot.End, New: nt.End, Days: days(ot.End, nt.End)}
+		}
+		if ot.Duration != nt.Duration {
+			td.Duration = &TextChange{Old: ot.Duration.String(), New: nt.Duration.String()}
+		}
+		if ot.Status != nt.Status {
+			td.Status = &TextChange{Old: ot.Status.String(), New: nt.Status.String()}
+		}
+		if ot.Progress != nt.Progress {
+			td.Progress = &ProgressChange{Old: ot.Progress, New: nt.Progress}
+		}
+		td.AddedDeps = missingDeps(nt.Dependencies, ot.Dependencies)
+		td.RemovedDeps = missingDeps(ot.Dependencies, nt.Dependencies)
+		if td.Rename != nil || td.Start != nil || td.End != nil || td.Duration != nil || td.Status != nil ||
+			td.Progress != nil || len(td.AddedDeps) > 0 || len(td.RemovedDeps) > 0 {
+			out.Tasks = append(out.Tasks, td)
+		}
+		if milestone && !ot.Start.Equal(nt.Start) {
+			out.Milestones = append(out.Milestones, MilestoneSlip{
+				ID:   nt.ID,
+				Name: nt.Name,
+				Old:  ot.Start,
+				New:  nt.Start,
+				Days: days(ot.Start, nt.Start),
+			})
+		}
+	}
+	for _, ot := range diffTasks(before) {
+		if seen[ot.ID] {
+			continue
+		}
+		out.Tasks = append(out.Tasks, TaskDiff{
+			ID:    ot.ID,
+			Name:  ot.Name,
+			Kind:  DiffRemoved,
+			Start: &DateChange{Old: ot.Start},
+			End:   &DateChange{Old: ot.End},
+		})
+	}
+	return out
+}
+
+// diffTasks 按源中顺序返回参与比较的任务（不含垂直标记）。
+func diffTasks(m parser.Model) []parser.Task {
+	var out []parser.Task
+	for _, sec := range m.Sections {
+		for _, t := range sec.Tasks {
+			if !t.IsVertical {
+				out = append(out, t)
+			}
+		}
+	}
+	return out
+}
+
+// missingDeps 返回 from 中存在而 in 中不存在的依赖（按 mermaid 语法文本比较）。
+func missingDeps(from, in []parser.Dependency) []string {
+	have := make(map[string]bool, len(in))
+	for _, d := range in {
+		have[d.String()] = true
+	}
+	var out []string
+	for _, d := range from {
+		if s := d.String(); !have[s] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func isMilestoneTask(t parser.Task) bool {
+	return t.IsMilestone || t.Duration.Value == 0
+}
+
+// diffTime 输出日期；带时刻（非零点、非日末）时附加时分。
+func diffTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	end := t.Add(time.Nanosecond)
+	if (t.Hour() == 0 && t.Minute() == 0) || (end.Hour() == 0 && end.Minute() == 0 && end.Second() == 0) {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04")
+}
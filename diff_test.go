@@ -0,0 +1,72 @@
+package go_mermaid_gantt
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	oldSrc := `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+section A
+Design :d1, 2025-03-03, 3d
+Build  :b1, after d1, 5d, 20%
+Docs   :o1, 2025-03-03, 2d
+Ship   :milestone, m1, after b1, 0d
+`
+	newSrc := `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+section A
+Design  :d1, 2025-03-03, 5d
+Develop :b1, after d1 +1d, 5d, active, 60%
+Review  :r1, 2025-03-04, 1d
+Ship    :milestone, m1, after b1, 0d
+`
+	d, err := Diff(t.Context(), Input{Source: oldSrc}, Input{Source: newSrc})
+	if err != nil {
+		t.Fatalf("diff failed: %v", err)
+	}
+	kinds := map[string]DiffKind{}
+	for _, td := range d.Tasks {
+		kinds[td.ID] = td.Kind
+	}
+	if kinds["r1"] != DiffAdded || kinds["o1"] != DiffRemoved || kinds["d1"] != DiffChanged {
+		t.Fatalf("unexpected kinds: %v", kinds)
+	}
+	b1 := d.Tasks[1]
+	if b1.ID != "b1" || b1.Rename == nil || b1.Rename.Old != "Build" || b1.Status == nil || b1.Status.New != "active" ||
+		b1.Progress == nil || b1.Progress.New != 60 || len(b1.AddedDeps) != 1 || b1.AddedDeps[0] != "after d1 +1d" ||
+		len(b1.RemovedDeps) != 1 || b1.Start == nil || b1.Start.Days != 3 {
+		t.Fatalf("unexpected b1 diff: %+v", b1)
+	}
+	if d1 := d.Tasks[0]; d1.Duration == nil || d1.Duration.Old != "3d" || d1.Start != nil || d1.End.Days != 2 {
+		t.Fatalf("unexpected d1 diff: %+v", d1)
+	}
+	// d1 延长 2 天、b1 再等待 1 天：m1 推迟 3 个工作日
+	if len(d.Milestones) != 1 || d.Milestones[0].ID != "m1" || d.Milestones[0].Days != 3 {
+		t.Fatalf("unexpected milestone slips: %+v", d.Milestones)
+	}
+
+	text := d.String()
+	for _, want := range []string{`+ r1 "Review"`, `- o1 "Docs"`, `renamed from "Build"`, "progress 20% -> 60%", "m1 \"Ship\" 2025-03-13 -> 2025-03-18 (+3d)"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("text output missing %q:\n%s", want, text)
+		}
+	}
+	raw, err := d.JSON()
+	if err != nil {
+		t.Fatalf("json failed: %v", err)
+	}
+	var decoded ScheduleDiff
+	if err := json.Unmarshal(raw, &decoded); err != nil || len(decoded.Tasks) != len(d.Tasks) {
+		t.Fatalf("json round trip failed: %v\n%s", err, raw)
+	}
+
+	same, err := Diff(t.Context(), Input{Source: oldSrc}, Input{Source: oldSrc})
+	if err != nil || !same.Empty() {
+		t.Fatalf("expected no changes, got %+v (%v)", same, err)
+	}
+}
@@ -0,0 +1,73 @@
+package go_mermaid_gantt
+
+import (
+	"context"
+	"time"
+
+	"github.com/pyroflux/go-mermaid-gantt/internal/parser"
+)
+
+// EVMetrics 为挣值指标：BAC 为完工预算，PV 为截至状态日的计划值，EV 为挣值，
+// SV = EV - PV，SPI = EV / PV（PV 为 0 时记 0）。
+type EVMetrics struct {
+	BAC float64
+	PV  float64
+	EV  float64
+	SV  float64
+	SPI float64
+}
+
+// TaskEarnedValue 为单个任务的挣值。
+type TaskEarnedValue struct {
+	ID       string
+	Name     string
+	Section  string
+	Planned  float64 // 截至状态日计划完成比例（0-1）
+	Complete float64 // 实际完成比例：进度百分比，done 任务为 1
+	EVMetrics
+}
+
+// SectionEarnedValue 为 section 汇总的挣值。
+type SectionEarnedValue struct {
+	Name string
+	EVMetrics
+}
+
+// EarnedValueReport 为截至状态日的挣值分析。
+type EarnedValueReport struct {
+	StatusDate time.Time
+	CostBased  bool // 任一任务写了 cost= 时按成本加权，否则按工作日工期加权
+	Tasks      []TaskEarnedValue
+	Sections   []SectionEarnedValue
+	Project    EVMetrics
+}
+
+// EarnedValue 排程 Input.Source 并计算截至状态日（含当日）的挣值；状态日取 Input.Today 或 todayMarker 日期，
+// 均未设置时为当天。
+func EarnedValue(ctx context.Context, in Input) (EarnedValueReport, error) {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return EarnedValueReport{}, err
+		}
+	}
+	model, err := loadModel(in)
+	if err != nil {
+		return EarnedValueReport{}, err
+	}
+	rep := parser.EarnedValue(model, parser.StatusDate(model))
+	out := EarnedValueReport{StatusDate: rep.StatusDate, CostBased: rep.CostBased, Project: EVMetrics(rep.Project)}
+	for _, t := range rep.Tasks {
+		out.Tasks = append(out.Tasks, TaskEarnedValue{
+			ID:        t.ID,
+			Name:      t.Name,
+			Section:   t.Section,
+			Planned:   t.Planned,
+			Complete:  t.Complete,
+			EVMetrics: EVMetrics(t.EVMetrics),
+		})
+	}
+	for _, s := range rep.Sections {
+		out.Sections = append(out.Sections, SectionEarnedValue{Name: s.Name, EVMetrics: EVMetrics(s.EVMetrics)})
+	}
+	return out, nil
+}
@@ -0,0 +1,74 @@
+package go_mermaid_gantt
+
+import (
+	"context"
+	"time"
+
+	"github.com/pyroflux/go-mermaid-gantt/internal/parser"
+)
+
+// Distribution 为三点估算的抽样分布。
+type Distribution int
+
+const (
+	DistributionPERT       Distribution = iota // beta-PERT（缺省）
+	DistributionTriangular                     // 三角分布
+)
+
+// SimulationOptions 控制蒙特卡洛模拟。
+type SimulationOptions struct {
+	Runs         int   // 模拟次数，0 表示 1000
+	Seed         int64 // 随机种子，相同种子与源得到相同结果
+	Distribution Distribution
+}
+
+// MilestoneForecast 为里程碑日期的分布：Planned 为按最可能工期排程的日期，其余为分位点。
+type MilestoneForecast struct {
+	ID      string
+	Name    string
+	Planned time.Time
+	P50     time.Time
+	P80     time.Time
+	P90     time.Time
+	P95     time.Time
+}
+
+// Forecast 为蒙特卡洛完成预测。
+type Forecast struct {
+	Runs       int
+	Milestones []MilestoneForecast
+	Finish     MilestoneForecast // 项目完成（全部任务的最晚结束），ID 为空
+}
+
+// Simulate 对源中的三点估算（`3d/5d/10d`）反复抽样工期并重新排程，返回各里程碑与项目完成的 P50/P80/P90/P95 日期。
+// 未写三点估算的任务工期固定；Input.LevelResources 为 true 时每次模拟均执行资源平衡。
+func Simulate(ctx context.Context, in Input, opt SimulationOptions) (Forecast, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		return Forecast{}, err
+	}
+	model, err := parseInput(in)
+	if err != nil {
+		return Forecast{}, err
+	}
+	fc, err := parser.Simulate(ctx, model, simulationOptions(in, opt))
+	if err != nil {
+		return Forecast{}, err
+	}
+	out := Forecast{Runs: fc.Runs, Finish: MilestoneForecast(fc.Finish)}
+	for _, f := range fc.Milestones {
+		out.Milestones = append(out.Milestones, MilestoneForecast(f))
+	}
+	return out, nil
+}
+
+func simulationOptions(in Input, opt SimulationOptions) parser.SimulationOptions {
+	return parser.SimulationOptions{
+		Runs:         opt.Runs,
+		Seed:         opt.Seed,
+		Distribution: parser.Distribution(opt.Distribution),
+		Level:        in.LevelResources,
+	}
+}
@@ -0,0 +1,72 @@
+package holidays
+
+import (
+	"fmt"
+	"time"
+)
+
+// cnPeriod 为国务院办公厅公布的一段放假安排及其调休上班日。
+type cnPeriod struct {
+	name     string
+	from, to string   // 放假起止（含）
+	workdays []string // 调休上班日
+}
+
+// chinaSchedule 按年份收录放假安排，新一年的通知发布后在此追加并递增 DatasetVersion。
+var chinaSchedule = map[int][]cnPeriod{
+	2024: {
+		{name: "元旦", from: "2024-01-01", to: "2024-01-01"},
+		{name: "春节", from: "2024-02-10", to: "2024-02-17", workdays: []string{"2024-02-04", "2024-02-18"}},
+		{name: "清明节", from: "2024-04-04", to: "2024-04-06", workdays: []string{"2024-04-07"}},
+		{name: "劳动节", from: "2024-05-01", to: "2024-05-05", workdays: []string{"2024-04-28", "2024-05-11"}},
+		{name: "端午节", from: "2024-06-10", to: "2024-06-10"},
+		{name: "中秋节", from: "2024-09-15", to: "2024-09-17", workdays: []string{"2024-09-14"}},
+		{name: "国庆节", from: "2024-10-01", to: "2024-10-07", workdays: []string{"2024-09-29", "2024-10-12"}},
+	},
+	2025: {
+		{name: "元旦", from: "2025-01-01", to: "2025-01-01"},
+		{name: "春节", from: "2025-01-28", to: "2025-02-04", workdays: []string{"2025-01-26", "2025-02-08"}},
+		{name: "清明节", from: "2025-04-04", to: "2025-04-06"},
+		{name: "劳动节", from: "2025-05-01", to: "2025-05-05", workdays: []string{"2025-04-27"}},
+		{name: "端午节", from: "2025-05-31", to: "2025-06-02"},
+		{name: "国庆节、中秋节", from: "2025-10-01", to: "2025-10-08", workdays: []string{"2025-09-28", "2025-10-11"}},
+	},
+	2026: {
+		{name: "元旦", from: "2026-01-01", to: "2026-01-03", workdays: []string{"2026-01-04"}},
+		{name: "春节", from: "2026-02-15", to: "2026-02-23", workdays: []string{"2026-02-14", "2026-02-28"}},
+		{name: "清明节", from: "2026-04-04", to: "2026-04-06"},
+		{name: "劳动节", from: "2026-05-01", to: "2026-05-05", workdays: []string{"2026-05-09"}},
+		{name: "端午节", from: "2026-06-19", to: "2026-06-21"},
+		{name: "中秋节", from: "2026-09-25", to: "2026-09-27"},
+		{name: "国庆节", from: "2026-10-01", to: "2026-10-07", workdays: []string{"2026-09-20", "2026-10-10"}},
+	},
+}
+
+func chinaHolidays(year int) ([]Holiday, error) {
+	periods, ok := chinaSchedule[year]
+	if !ok {
+		return nil, fmt.Errorf("no published schedule in dataset %s", DatasetVersion)
+	}
+	var out []Holiday
+	for _, p := range periods {
+		from, err := time.Parse("2006-01-02", p.from)
+		if err != nil {
+			return nil, err
+		}
+		to, err := time.Parse("2006-01-02", p.to)
+		if err != nil {
+			return nil, err
+		}
+		for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+			out = append(out, Holiday{Date: d, Name: p.name})
+		}
+		for _, w := range p.workdays {
+			d, err := time.Parse("2006-01-02", w)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, Holiday{Date: d, Name: p.name + "调休", Workday: true})
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,101 @@
+package holidays
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DatasetVersion 为内置节假日数据的版本，数据或规则变化时递增。
+const DatasetVersion = "2026.1"
+
+// 规则计算的年份范围：早于 minRuleYear 的年份存在未收录的临时调整。
+const (
+	minRuleYear = 2022
+	maxRuleYear = 2099
+	daysPerWeek = 7
+)
+
+// Holiday 为某一天的假日或调休上班日。
+type Holiday struct {
+	Date    time.Time // UTC 零点
+	Name    string
+	Workday bool // true 表示调休上班日（如中国的补班周末）
+}
+
+// Countries 返回支持的国家代码。
+func Countries() []string {
+	out := make([]string, 0, len(providers))
+	for code := range providers {
+		out = append(out, code)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Lookup 返回某国某年的假日与调休上班日，按日期排序。
+// CN 按国务院公布的年度安排逐年收录，其余国家按法定规则计算（含顺延补假）。
+func Lookup(country string, year int) ([]Holiday, error) {
+	code := strings.ToUpper(strings.TrimSpace(country))
+	provider, ok := providers[code]
+	if !ok {
+		return nil, fmt.Errorf("unknown holiday country %q (supported: %s)", country, strings.Join(Countries(), ", "))
+	}
+	out, err := provider(year)
+	if err != nil {
+		return nil, fmt.Errorf("holidays %s %d: %w", code, year, err)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	return out, nil
+}
+
+var providers = map[string]func(year int) ([]Holiday, error){
+	"CN": chinaHolidays,
+	"US": ruleBased(unitedStates),
+	"DE": ruleBased(germany),
+	"JP": ruleBased(japan),
+	"UK": ruleBased(unitedKingdom),
+	"GB": ruleBased(unitedKingdom),
+}
+
+func ruleBased(fn func(year int) []Holiday) func(int) ([]Holiday, error) {
+	return func(year int) ([]Holiday, error) {
+		if year < minRuleYear || year > maxRuleYear {
+			return nil, fmt.Errorf("year out of supported range %d-%d", minRuleYear, maxRuleYear)
+		}
+		return fn(year), nil
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// nthWeekday 返回某月第 n 个星期几；n 为负数时从月末倒数。
+func nthWeekday(year int, month time.Month, wd time.Weekday, n int) time.Time {
+	if n > 0 {
+		first := date(year, month, 1)
+		offset := (int(wd) - int(first.Weekday()) + daysPerWeek) % daysPerWeek
+		return first.AddDate(0, 0, offset+(n-1)*daysPerWeek)
+	}
+	last := date(year, month+1, 0)
+	offset := (int(last.Weekday()) - int(wd) + daysPerWeek) % daysPerWeek
+	return last.AddDate(0, 0, -offset+(n+1)*daysPerWeek)
+}
+
+// easter 按 Anonymous Gregorian 算法计算复活节日期。
+func easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
@@ -0,0 +1,109 @@
+package holidays
+
+import (
+	"testing"
+	"time"
+)
+
+func find(list []Holiday, name string) (Holiday, bool) {
+	for _, h := range list {
+		if h.Name == name {
+			return h, true
+		}
+	}
+	return Holiday{}, false
+}
+
+func TestLookup_Rules(t *testing.T) {
+	cases := []struct {
+		country string
+		year    int
+		name    string
+		want    string
+	}{
+		{"US", 2025, "Thanksgiving Day", "2025-11-27"},
+		{"US", 2026, "Independence Day", "2026-07-03"}, // 周六提前到周五
+		{"US", 2025, "Memorial Day", "2025-05-26"},
+		{"DE", 2025, "Karfreitag", "2025-04-18"},
+		{"DE", 2025, "Pfingstmontag", "2025-06-09"},
+		{"UK", 2027, "Boxing Day", "2027-12-28"}, // 圣诞周六、节礼日周日
+		{"UK", 2022, "Spring bank holiday", "2022-06-02"},
+		{"JP", 2025, "Vernal Equinox Day", "2025-03-20"},
+		{"JP", 2025, "Autumnal Equinox Day", "2025-09-23"},
+		{"JP", 2026, "Citizens' Holiday", "2026-09-22"},
+	}
+	for _, c := range cases {
+		list, err := Lookup(c.country, c.year)
+		if err != nil {
+			t.Fatalf("%s %d: %v", c.country, c.year, err)
+		}
+		h, ok := find(list, c.name)
+		if !ok || h.Date.Format("2006-01-02") != c.want {
+			t.Fatalf("%s %d %s expected %s, got %v", c.country, c.year, c.name, c.want, h.Date)
+		}
+	}
+}
+
+func TestLookup_JapanSubstitute(t *testing.T) {
+	list, err := Lookup("jp", 2025)
+	if err != nil {
+		t.Fatalf("lookup failed: %v", err)
+	}
+	// 2025-02-23 为周日，02-24 补休；05-04 周日，05-06 补休
+	for _, want := range []string{"2025-02-24", "2025-05-06"} {
+		found := false
+		for _, h := range list {
+			found = found || (h.Name == "Substitute Holiday" && h.Date.Format("2006-01-02") == want)
+		}
+		if !found {
+			t.Fatalf("expected substitute holiday on %s", want)
+		}
+	}
+}
+
+func TestLookup_China(t *testing.T) {
+	list, err := Lookup("CN", 2025)
+	if err != nil {
+		t.Fatalf("lookup failed: %v", err)
+	}
+	off, work := 0, 0
+	for _, h := range list {
+		if h.Workday {
+			work++
+			if h.Date.Weekday() != time.Saturday && h.Date.Weekday() != time.Sunday {
+				t.Fatalf("makeup workday %s should fall on a weekend", h.Date.Format("2006-01-02"))
+			}
+		} else {
+			off++
+		}
+	}
+	if off != 28 || work != 5 {
+		t.Fatalf("expected 28 holidays and 5 makeup workdays, got %d/%d", off, work)
+	}
+
+	// 2026 年：元旦 3、春节 9、清明 3、劳动节 5、端午 3、中秋 3、国庆 7 天，调休上班 6 天
+	list, err = Lookup("CN", 2026)
+	if err != nil {
+		t.Fatalf("lookup 2026 failed: %v", err)
+	}
+	off, work = 0, 0
+	for _, h := range list {
+		if !h.Workday {
+			off++
+			continue
+		}
+		work++
+		if h.Date.Weekday() != time.Saturday && h.Date.Weekday() != time.Sunday {
+			t.Fatalf("makeup workday %s should fall on a weekend", h.Date.Format("2006-01-02"))
+		}
+	}
+	if off != 33 || work != 6 {
+		t.Fatalf("expected 33 holidays and 6 makeup workdays in 2026, got %d/%d", off, work)
+	}
+	if _, err := Lookup("CN", 2019); err == nil {
+		t.Fatalf("expected error for year outside dataset")
+	}
+	if _, err := Lookup("FR", 2025); err == nil {
+		t.Fatalf("expected error for unknown country")
+	}
+}
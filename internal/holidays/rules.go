@@ -0,0 +1,174 @@
+package holidays
+
+import "time"
+
+// observedUS 为美国联邦假日的顺延规则：周六提前到周五，周日顺延到周一。
+func observedUS(d time.Time) time.Time {
+	switch d.Weekday() {
+	case time.Saturday:
+		return d.AddDate(0, 0, -1)
+	case time.Sunday:
+		return d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// unitedStates 返回联邦假日（按实际放假日）。
+func unitedStates(year int) []Holiday {
+	return []Holiday{
+		{Date: observedUS(date(year, time.January, 1)), Name: "New Year's Day"},
+		{Date: nthWeekday(year, time.January, time.Monday, 3), Name: "Martin Luther King Jr. Day"},
+		{Date: nthWeekday(year, time.February, time.Monday, 3), Name: "Washington's Birthday"},
+		{Date: nthWeekday(year, time.May, time.Monday, -1), Name: "Memorial Day"},
+		{Date: observedUS(date(year, time.June, 19)), Name: "Juneteenth"},
+		{Date: observedUS(date(year, time.July, 4)), Name: "Independence Day"},
+		{Date: nthWeekday(year, time.September, time.Monday, 1), Name: "Labor Day"},
+		{Date: nthWeekday(year, time.October, time.Monday, 2), Name: "Columbus Day"},
+		{Date: observedUS(date(year, time.November, 11)), Name: "Veterans Day"},
+		{Date: nthWeekday(year, time.November, time.Thursday, 4), Name: "Thanksgiving Day"},
+		{Date: observedUS(date(year, time.December, 25)), Name: "Christmas Day"},
+	}
+}
+
+// germany 返回全国性法定假日（不含各州假日）。
+func germany(year int) []Holiday {
+	e := easter(year)
+	return []Holiday{
+		{Date: date(year, time.January, 1), Name: "Neujahr"},
+		{Date: e.AddDate(0, 0, -2), Name: "Karfreitag"},
+		{Date: e.AddDate(0, 0, 1), Name: "Ostermontag"},
+		{Date: date(year, time.May, 1), Name: "Tag der Arbeit"},
+		{Date: e.AddDate(0, 0, 39), Name: "Christi Himmelfahrt"},
+		{Date: e.AddDate(0, 0, 50), Name: "Pfingstmontag"},
+		{Date: date(year, time.October, 3), Name: "Tag der Deutschen Einheit"},
+		{Date: date(year, time.December, 25), Name: "1. Weihnachtstag"},
+		{Date: date(year, time.December, 26), Name: "2. Weihnachtstag"},
+	}
+}
+
+// ukSpecial 收录英格兰及威尔士的一次性银行假日与调整。
+var ukSpecial = map[int]struct {
+	add  []Holiday
+	drop []string // 被调整掉的常规假日名称
+}{
+	2022: {
+		add: []Holiday{
+			{Date: date(2022, time.June, 2), Name: "Spring bank holiday"},
+			{Date: date(2022, time.June, 3), Name: "Platinum Jubilee bank holiday"},
+			{Date: date(2022, time.September, 19), Name: "State Funeral of Queen Elizabeth II"},
+		},
+		drop: []string{"Spring bank holiday"},
+	},
+	2023: {
+		add: []Holiday{{Date: date(2023, time.May, 8), Name: "Coronation bank holiday"}},
+	},
+}
+
+// unitedKingdom 返回英格兰及威尔士的银行假日，落在周末时顺延到随后的工作日。
+func unitedKingdom(year int) []Holiday {
+	e := easter(year)
+	out := []Holiday{
+		{Date: substituteUK(date(year, time.January, 1), nil), Name: "New Year's Day"},
+		{Date: e.AddDate(0, 0, -2), Name: "Good Friday"},
+		{Date: e.AddDate(0, 0, 1), Name: "Easter Monday"},
+		{Date: nthWeekday(year, time.May, time.Monday, 1), Name: "Early May bank holiday"},
+		{Date: nthWeekday(year, time.May, time.Monday, -1), Name: "Spring bank holiday"},
+		{Date: nthWeekday(year, time.August, time.Monday, -1), Name: "Summer bank holiday"},
+	}
+	christmas := substituteUK(date(year, time.December, 25), nil)
+	boxing := substituteUK(date(year, time.December, 26), []time.Time{christmas})
+	out = append(out, Holiday{Date: christmas, Name: "Christmas Day"}, Holiday{Date: boxing, Name: "Boxing Day"})
+
+	special, ok := ukSpecial[year]
+	if !ok {
+		return out
+	}
+	kept := out[:0]
+	for _, h := range out {
+		dropped := false
+		for _, name := range special.drop {
+			dropped = dropped || h.Name == name
+		}
+		if !dropped {
+			kept = append(kept, h)
+		}
+	}
+	return append(kept, special.add...)
+}
+
+// substituteUK 将周末顺延到下一个未被占用的工作日。
+func substituteUK(d time.Time, taken []time.Time) time.Time {
+	for {
+		busy := d.Weekday() == time.Saturday || d.Weekday() == time.Sunday
+		for _, t := range taken {
+			busy = busy || t.Equal(d)
+		}
+		if !busy {
+			return d
+		}
+		d = d.AddDate(0, 0, 1)
+	}
+}
+
+// japan 返回日本的国民の祝日，含振替休日与国民の休日。
+func japan(year int) []Holiday {
+	list := []Holiday{
+		{Date: date(year, time.January, 1), Name: "New Year's Day"},
+		{Date: nthWeekday(year, time.January, time.Monday, 2), Name: "Coming of Age Day"},
+		{Date: date(year, time.February, 11), Name: "National Foundation Day"},
+		{Date: date(year, time.February, 23), Name: "Emperor's Birthday"},
+		{Date: date(year, time.March, equinoxDay(year, springEquinoxBase)), Name: "Vernal Equinox Day"},
+		{Date: date(year, time.April, 29), Name: "Showa Day"},
+		{Date: date(year, time.May, 3), Name: "Constitution Memorial Day"},
+		{Date: date(year, time.May, 4), Name: "Greenery Day"},
+		{Date: date(year, time.May, 5), Name: "Children's Day"},
+		{Date: nthWeekday(year, time.July, time.Monday, 3), Name: "Marine Day"},
+		{Date: date(year, time.August, 11), Name: "Mountain Day"},
+		{Date: nthWeekday(year, time.September, time.Monday, 3), Name: "Respect for the Aged Day"},
+		{Date: date(year, time.September, equinoxDay(year, autumnEquinoxBase)), Name: "Autumnal Equinox Day"},
+		{Date: nthWeekday(year, time.October, time.Monday, 2), Name: "Sports Day"},
+		{Date: date(year, time.November, 3), Name: "Culture Day"},
+		{Date: date(year, time.November, 23), Name: "Labor Thanksgiving Day"},
+	}
+	isHoliday := func(d time.Time) bool {
+		for _, h := range list {
+			if h.Date.Equal(d) {
+				return true
+			}
+		}
+		return false
+	}
+	// 国民の休日：夹在两个祝日之间的平日
+	for _, h := range append([]Holiday(nil), list...) {
+		mid := h.Date.AddDate(0, 0, 1)
+		if !isHoliday(mid) && mid.Weekday() != time.Sunday && isHoliday(mid.AddDate(0, 0, 1)) {
+			list = append(list, Holiday{Date: mid, Name: "Citizens' Holiday"})
+		}
+	}
+	// 振替休日：祝日逢周日时，其后首个非祝日补休
+	for _, h := range append([]Holiday(nil), list...) {
+		if h.Date.Weekday() != time.Sunday {
+			continue
+		}
+		sub := h.Date.AddDate(0, 0, 1)
+		for isHoliday(sub) {
+			sub = sub.AddDate(0, 0, 1)
+		}
+		list = append(list, Holiday{Date: sub, Name: "Substitute Holiday"})
+	}
+	return list
+}
+
+// 春分、秋分日的近似公式（适用于 1980-2099 年）。
+const (
+	springEquinoxBase = 20.8431
+	autumnEquinoxBase = 23.2488
+	equinoxDrift      = 0.242194
+	equinoxBaseYear   = 1980
+	leapCycle         = 4
+)
+
+func equinoxDay(year int, base float64) int {
+	n := year - equinoxBaseYear
+	return int(base+equinoxDrift*float64(n)) - n/leapCycle
+}
@@ -0,0 +1,480 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405"
+	// maxExpandYear 为无 COUNT/UNTIL 的年度规则展开的上限年份。
+	maxExpandYear = 2100
+	// maxOccurrences 限制单条规则展开的实例数，防止异常规则失控。
+	maxOccurrences = 1000
+	maxEventDays   = 366
+	daysPerWeek    = 7
+	hoursPerDay    = 24
+	monthsPerYear  = 12
+	maxMonthDay    = 31
+	maxWeekOrdinal = 5
+)
+
+// Event 为日历中的一个全天（可跨多天）事件。
+type Event struct {
+	Summary    string
+	Categories []string
+	Start      time.Time // 首日（UTC 零点）
+	Days       int       // 持续天数，至少 1
+	Rule       *Rule     // RRULE，nil 表示不重复
+	ExDates    []time.Time
+	Line       int // BEGIN:VEVENT 所在行
+}
+
+// Rule 为支持的 RRULE 子集：FREQ=YEARLY，可带 INTERVAL/COUNT/UNTIL/BYMONTH/BYMONTHDAY/BYDAY。
+type Rule struct {
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByMonth    []time.Month
+	ByMonthDay []int
+	ByDay      []WeekdayNum
+}
+
+// WeekdayNum 为 BYDAY 中的一项，如 4TH、-1MO；N 为 0 表示该月所有该星期几。
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// Calendar 为解析后的日历。
+type Calendar struct {
+	Events []Event
+	// Skipped 记录被忽略的事件（定时事件、已取消事件、不支持的 RRULE 或 DURATION）数量。
+	Skipped int
+}
+
+// errUnsupported 标记合法但不支持的取值（如非 YEARLY 的 RRULE、PT1H 时长），对应事件被跳过而非报错。
+var errUnsupported = errors.New("unsupported")
+
+// rawProperty 暂存事件的 RRULE/DURATION 原文，待确认为全天事件后再解析。
+type rawProperty struct {
+	line  int
+	value string
+}
+
+// Parse 读取 RFC 5545 日历，保留全天事件；定时事件、STATUS:CANCELLED 事件，以及使用不支持的
+// RRULE（非 YEARLY）或 DURATION（如 PT1H）的事件被忽略并计入 Skipped。
+func Parse(r io.Reader) (Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return Calendar{}, err
+	}
+	var cal Calendar
+	var cur *Event
+	var allDay, cancelled bool
+	var end time.Time
+	var rawRule, rawDur *rawProperty
+	depth := 0 // VEVENT 内嵌组件（如 VALARM）的层数，仅接受 depth 为 0 的属性
+	for _, ln := range lines {
+		name, params, value := splitProperty(ln.text)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			cur = &Event{Line: ln.no}
+			allDay, cancelled, end, rawRule, rawDur, depth = false, false, time.Time{}, nil, nil, 0
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if cur == nil {
+				return Calendar{}, fmt.Errorf("line %d: END:VEVENT without BEGIN", ln.no)
+			}
+			if cur.Start.IsZero() {
+				return Calendar{}, fmt.Errorf("line %d: VEVENT without DTSTART", cur.Line)
+			}
+			if !allDay || cancelled {
+				cal.Skipped++
+				cur = nil
+				continue
+			}
+			dur, rule, err := parseDeferred(rawDur, rawRule)
+			if errors.Is(err, errUnsupported) {
+				cal.Skipped++
+				cur = nil
+				continue
+			}
+			if err != nil {
+				return Calendar{}, err
+			}
+			cur.Rule = rule
+			cur.Days = 1
+			if !end.IsZero() {
+				cur.Days = int(end.Sub(cur.Start).Hours() / hoursPerDay)
+			} else if dur > 0 {
+				cur.Days = dur
+			}
+			if cur.Days < 1 || cur.Days > maxEventDays {
+				return Calendar{}, fmt.Errorf("line %d: invalid event length of %d days", cur.Line, cur.Days)
+			}
+			cal.Events = append(cal.Events, *cur)
+			cur = nil
+		case cur == nil:
+			continue
+		case name == "BEGIN":
+			depth++
+		case name == "END":
+			if depth == 0 {
+				return Calendar{}, fmt.Errorf("line %d: END:%s without BEGIN", ln.no, value)
+			}
+			depth--
+		case depth > 0:
+			continue
+		case name == "SUMMARY":
+			cur.Summary = unescape(value)
+		case name == "CATEGORIES":
+			for _, c := range strings.Split(value, ",") {
+				if c = strings.TrimSpace(unescape(c)); c != "" {
+					cur.Categories = append(cur.Categories, c)
+				}
+			}
+		case name == "STATUS":
+			cancelled = strings.EqualFold(value, "CANCELLED")
+		case name == "DTSTART":
+			t, isDate, err := parseDateValue(value, params)
+			if err != nil {
+				return Calendar{}, fmt.Errorf("line %d: %w", ln.no, err)
+			}
+			cur.Start, allDay = t, isDate
+		case name == "DTEND":
+			t, _, err := parseDateValue(value, params)
+			if err != nil {
+				return Calendar{}, fmt.Errorf("line %d: %w", ln.no, err)
+			}
+			end = t
+		case name == "DURATION":
+			rawDur = &rawProperty{line: ln.no, value: value}
+		case name == "RRULE":
+			rawRule = &rawProperty{line: ln.no, value: value}
+		case name == "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				t, _, err := parseDateValue(v, params)
+				if err != nil {
+					return Calendar{}, fmt.Errorf("line %d: %w", ln.no, err)
+				}
+				cur.ExDates = append(cur.ExDates, t)
+			}
+		}
+	}
+	if cur != nil {
+		return Calendar{}, fmt.Errorf("line %d: VEVENT is not closed", cur.Line)
+	}
+	return cal, nil
+}
+
+// parseDeferred 解析全天事件暂存的 DURATION 与 RRULE；不支持的取值返回包装 errUnsupported 的错误。
+func parseDeferred(rawDur, rawRule *rawProperty) (int, *Rule, error) {
+	var dur int
+	var rule *Rule
+	if rawDur != nil {
+		d, err := parseDayDuration(rawDur.value)
+		if err != nil {
+			return 0, nil, fmt.Errorf("line %d: %w", rawDur.line, err)
+		}
+		dur = d
+	}
+	if rawRule != nil {
+		r, err := parseRule(rawRule.value)
+		if err != nil {
+			return 0, nil, fmt.Errorf("line %d: %w", rawRule.line, err)
+		}
+		rule = r
+	}
+	return dur, rule, nil
+}
+
+// Dates 展开事件覆盖的所有日期（含重复实例，扣除 EXDATE）。
+func (e Event) Dates() []time.Time {
+	var out []time.Time
+	for _, start := range e.occurrences() {
+		if containsDay(e.ExDates, start) {
+			continue
+		}
+		for i := 0; i < e.Days; i++ {
+			out = append(out, start.AddDate(0, 0, i))
+		}
+	}
+	return out
+}
+
+// IsWorkday 判断事件是否标记调休上班日：CATEGORIES 含 workday/working day，或标题含 "补班"。
+// 英文标题不作判断，"Non-working day" 之类的否定说法会把假日误判为上班日。
+func (e Event) IsWorkday() bool {
+	for _, c := range e.Categories {
+		switch strings.ToLower(c) {
+		case "workday", "working day", "work day", "补班":
+			return true
+		}
+	}
+	return strings.Contains(e.Summary, "补班")
+}
+
+func (e Event) occurrences() []time.Time {
+	if e.Rule == nil {
+		return []time.Time{e.Start}
+	}
+	r := e.Rule
+	interval := r.Interval
+	if interval <= 0 {
+		interval = 1
+	}
+	var out []time.Time
+	for year := e.Start.Year(); year <= maxExpandYear && len(out) < maxOccurrences; year += interval {
+		for _, d := range r.datesInYear(year, e.Start) {
+			if d.Before(e.Start) {
+				continue
+			}
+			if !r.Until.IsZero() && d.After(r.Until) {
+				return out
+			}
+			out = append(out, d)
+			if r.Count > 0 && len(out) >= r.Count {
+				return out
+			}
+		}
+	}
+	return out
+}
+
+// datesInYear 按 BYMONTH/BYMONTHDAY/BYDAY 计算某年的实例，缺省沿用 DTSTART 的月、日。
+func (r *Rule) datesInYear(year int, start time.Time) []time.Time {
+	months := r.ByMonth
+	if len(months) == 0 {
+		months = []time.Month{start.Month()}
+	}
+	var out []time.Time
+	for _, m := range months {
+		first := time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+		last := first.AddDate(0, 1, -1).Day()
+		switch {
+		case len(r.ByDay) > 0:
+			for _, wd := range r.ByDay {
+				out = append(out, weekdaysInMonth(first, last, wd)...)
+			}
+		case len(r.ByMonthDay) > 0:
+			for _, md := range r.ByMonthDay {
+				if md < 0 {
+					md = last + md + 1
+				}
+				if md >= 1 && md <= last {
+					out = append(out, first.AddDate(0, 0, md-1))
+				}
+			}
+		default:
+			// 2 月 29 日等不存在的日期在该年跳过
+			if start.Day() <= last {
+				out = append(out, first.AddDate(0, 0, start.Day()-1))
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Before(out[j]) })
+	return out
+}
+
+func weekdaysInMonth(first time.Time, last int, wd WeekdayNum) []time.Time {
+	var all []time.Time
+	offset := (int(wd.Weekday) - int(first.Weekday()) + daysPerWeek) % daysPerWeek
+	for d := 1 + offset; d <= last; d += daysPerWeek {
+		all = append(all, first.AddDate(0, 0, d-1))
+	}
+	switch {
+	case wd.N == 0:
+		return all
+	case wd.N > 0 && wd.N <= len(all):
+		return all[wd.N-1 : wd.N]
+	case wd.N < 0 && -wd.N <= len(all):
+		return all[len(all)+wd.N : len(all)+wd.N+1]
+	}
+	return nil
+}
+
+func parseRule(value string) (*Rule, error) {
+	rule := &Rule{}
+	freq := ""
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid RRULE part %q", part)
+		}
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			freq = strings.ToUpper(val)
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(val)
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(val)
+		case "UNTIL":
+			rule.Until, _, err = parseDateValue(val, nil)
+		case "BYMONTH":
+			for _, v := range strings.Split(val, ",") {
+				m, convErr := strconv.Atoi(v)
+				if convErr != nil || m < 1 || m > monthsPerYear {
+					return nil, fmt.Errorf("invalid BYMONTH %q", val)
+				}
+				rule.ByMonth = append(rule.ByMonth, time.Month(m))
+			}
+		case "BYMONTHDAY":
+			for _, v := range strings.Split(val, ",") {
+				d, convErr := strconv.Atoi(v)
+				if convErr != nil || d == 0 || d > maxMonthDay || d < -maxMonthDay {
+					return nil, fmt.Errorf("invalid BYMONTHDAY %q", val)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, d)
+			}
+		case "BYDAY":
+			for _, v := range strings.Split(val, ",") {
+				wd, convErr := parseWeekdayNum(v)
+				if convErr != nil {
+					return nil, convErr
+				}
+				rule.ByDay = append(rule.ByDay, wd)
+			}
+		case "WKST":
+			// 年度规则不受周起始影响
+		default:
+			return nil, fmt.Errorf("%w RRULE part %q", errUnsupported, key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid RRULE %s %q", key, val)
+		}
+	}
+	switch freq {
+	case "YEARLY":
+	case "":
+		return nil, fmt.Errorf("RRULE without FREQ")
+	default:
+		return nil, fmt.Errorf("%w RRULE FREQ %q (only YEARLY)", errUnsupported, freq)
+	}
+	return rule, nil
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+func parseWeekdayNum(s string) (WeekdayNum, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if len(s) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", s)
+	}
+	wd, ok := weekdayCodes[s[len(s)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", s)
+	}
+	out := WeekdayNum{Weekday: wd}
+	if num := s[:len(s)-2]; num != "" {
+		n, err := strconv.Atoi(num)
+		if err != nil || n == 0 || n > maxWeekOrdinal || n < -maxWeekOrdinal {
+			return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", s)
+		}
+		out.N = n
+	}
+	return out, nil
+}
+
+// parseDateValue 解析 DATE 或 DATE-TIME 值，返回 UTC 当天零点及是否为纯日期。
+func parseDateValue(value string, params map[string]string) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if len(value) == len(dateLayout) || strings.EqualFold(params["VALUE"], "DATE") {
+		t, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+		return t, true, nil
+	}
+	t, err := time.Parse(dateTimeLayout, strings.TrimSuffix(value, "Z"))
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date-time %q", value)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), false, nil
+}
+
+// parseDayDuration 解析 P1D、P2W 形式的持续时间；含时分秒的时长（如 PT1H、P1DT2H）不支持。
+func parseDayDuration(value string) (int, error) {
+	v := strings.ToUpper(strings.TrimSpace(value))
+	if !strings.HasPrefix(v, "P") || len(v) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	if strings.Contains(v, "T") {
+		return 0, fmt.Errorf("%w duration %q", errUnsupported, value)
+	}
+	n, err := strconv.Atoi(v[1 : len(v)-1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	switch v[len(v)-1] {
+	case 'D':
+		return n, nil
+	case 'W':
+		return n * daysPerWeek, nil
+	}
+	return 0, fmt.Errorf("%w duration %q", errUnsupported, value)
+}
+
+type contentLine struct {
+	no   int
+	text string
+}
+
+// unfold 合并以空格或制表符开头的续行（RFC 5545 3.1）。
+func unfold(r io.Reader) ([]contentLine, error) {
+	scanner := bufio.NewScanner(r)
+	var out []contentLine
+	no := 0
+	for scanner.Scan() {
+		no++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(out) > 0 {
+			out[len(out)-1].text += text[1:]
+			continue
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		out = append(out, contentLine{no: no, text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read calendar: %w", err)
+	}
+	return out, nil
+}
+
+// splitProperty 拆分 "NAME;PARAM=V:VALUE"。
+func splitProperty(line string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			params[strings.ToUpper(k)] = v
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+func unescape(s string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
+
+func containsDay(list []time.Time, t time.Time) bool {
+	for _, d := range list {
+		if d.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const sample = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:New Year\r\n" +
+	"DTSTART;VALUE=DATE:20240101\r\n" +
+	"RRULE:FREQ=YEARLY;COUNT=3\r\n" +
+	"EXDATE;VALUE=DATE:20250101\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Thanks\r\n" +
+	" giving\r\n" +
+	"DTSTART;VALUE=DATE:20241128\r\n" +
+	"RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=4TH;UNTIL=20251231\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Spring Festival\r\n" +
+	"DTSTART;VALUE=DATE:20250128\r\n" +
+	"DTEND;VALUE=DATE:20250205\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Standup\r\n" +
+	"DTSTART:20250106T090000Z\r\n" +
+	"DTEND:20250106T091500Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:春节补班\r\n" +
+	"DTSTART;VALUE=DATE:20250126\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse_Events(t *testing.T) {
+	cal, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if len(cal.Events) != 4 || cal.Skipped != 1 {
+		t.Fatalf("expected 4 all-day events and 1 skipped, got %d/%d", len(cal.Events), cal.Skipped)
+	}
+	format := func(days []time.Time) string {
+		out := make([]string, len(days))
+		for i, d := range days {
+			out[i] = d.Format("2006-01-02")
+		}
+		return strings.Join(out, ",")
+	}
+	if got := format(cal.Events[0].Dates()); got != "2024-01-01,2026-01-01" {
+		t.Fatalf("yearly with EXDATE got %s", got)
+	}
+	if cal.Events[1].Summary != "Thanksgiving" {
+		t.Fatalf("folded summary got %q", cal.Events[1].Summary)
+	}
+	if got := format(cal.Events[1].Dates()); got != "2024-11-28,2025-11-27" {
+		t.Fatalf("BYDAY=4TH got %s", got)
+	}
+	if got := len(cal.Events[2].Dates()); got != 8 {
+		t.Fatalf("multi-day event expected 8 days, got %d", got)
+	}
+	if cal.Events[2].IsWorkday() || !cal.Events[3].IsWorkday() {
+		t.Fatalf("workday detection mismatch")
+	}
+	if (Event{Summary: "Non-working day"}).IsWorkday() || !(Event{Summary: "Bridge", Categories: []string{"Working Day"}}).IsWorkday() {
+		t.Fatalf("workday detection must rely on categories, not English summaries")
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	cases := []string{
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20250101\n",
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20250101\nRRULE:FREQ=YEARLY;COUNT=x\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:2025-01-01\nEND:VEVENT\n",
+	}
+	for _, src := range cases {
+		if _, err := Parse(strings.NewReader(src)); err == nil {
+			t.Fatalf("expected error for %q", src)
+		}
+	}
+}
+
+func TestParse_SkipsUnsupportedEvents(t *testing.T) {
+	src := `BEGIN:VCALENDAR
+BEGIN:VEVENT
+SUMMARY:Weekly sync
+DTSTART:20250106T090000Z
+DURATION:PT1H
+RRULE:FREQ=WEEKLY;BYDAY=MO
+END:VEVENT
+BEGIN:VEVENT
+SUMMARY:Sprint review
+DTSTART;VALUE=DATE:20250110
+RRULE:FREQ=MONTHLY;BYDAY=2FR
+END:VEVENT
+BEGIN:VEVENT
+SUMMARY:Half day
+DTSTART;VALUE=DATE:20250120
+DURATION:PT4H
+END:VEVENT
+BEGIN:VEVENT
+SUMMARY:New Year
+DTSTART;VALUE=DATE:20250101
+RRULE:FREQ=YEARLY;COUNT=2
+END:VEVENT
+END:VCALENDAR
+`
+	cal, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("unsupported rules should not fail the calendar: %v", err)
+	}
+	if len(cal.Events) != 1 || cal.Events[0].Summary != "New Year" || cal.Skipped != 3 {
+		t.Fatalf("expected 1 event and 3 skipped, got %d events, %d skipped", len(cal.Events), cal.Skipped)
+	}
+}
+
+func TestParse_IgnoresNestedComponents(t *testing.T) {
+	src := `BEGIN:VCALENDAR
+BEGIN:VEVENT
+SUMMARY:National Day
+DTSTART;VALUE=DATE:20251001
+BEGIN:VALARM
+ACTION:DISPLAY
+SUMMARY:Reminder
+DURATION:PT15M
+TRIGGER:-PT15M
+END:VALARM
+END:VEVENT
+END:VCALENDAR
+`
+	cal, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if len(cal.Events) != 1 || cal.Skipped != 0 {
+		t.Fatalf("expected 1 event and 0 skipped, got %d/%d", len(cal.Events), cal.Skipped)
+	}
+	if ev := cal.Events[0]; ev.Summary != "National Day" || ev.Days != 1 {
+		t.Fatalf("alarm properties leaked into the event: %+v", ev)
+	}
+}
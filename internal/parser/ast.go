@@ -1,6 +1,11 @@
 package parser
 
-import "time"
+import (
+	"strconv"
+	"time"
+
+	"github.com/pyroflux/go-mermaid-gantt/calendar"
+)
 
 // DurationUnit 表示持续时间单位。
 type DurationUnit int
@@ -28,8 +33,34 @@ const (
 type DependencyType int
 
 const (
-	DepAfter DependencyType = iota
-	DepBefore
+	DepAfter        DependencyType = iota // 完成-开始（FS）
+	DepBefore                             // 结束不晚于目标开始
+	DepStartStart                         // 开始-开始（SS）：with / ss
+	DepFinishFinish                       // 完成-完成（FF）：finishwith / ff
+	DepStartFinish                        // 开始-完成（SF）：sf，目标开始后方可完成
+)
+
+// ConstraintType 表示任务日期约束类型。
+// noLaterThan 在前推排程中是提示性的：任务已按依赖尽早开始，依赖使其晚于该日时不会被提前
+// （提前会破坏依赖），只给出警告；倒排（scheduleFrom end）时作为后排的上限。
+type ConstraintType int
+
+const (
+	ConstraintNoEarlierThan ConstraintType = iota // noEarlierThan：开始不早于该日
+	ConstraintNoLaterThan                         // noLaterThan：开始不晚于该日，前推时仅提示（见上）
+	ConstraintMustStartOn                         // mustStartOn：固定于该日开始，与依赖冲突时给出警告
+	ConstraintDeadline                            // deadline：应于该日（含）前完成，逾期时给出警告
+)
+
+// WarningKind 表示排程警告类别。
+type WarningKind int
+
+const (
+	WarningDeadlineMissed WarningKind = iota
+	WarningConstraintViolated
+	WarningOverAllocated
+	WarningProjectEndMissed
+	WarningDuplicateID
 )
 
 // DurationSpec 捕获 mermaid 中的持续时间定义。
@@ -42,6 +73,47 @@ type DurationSpec struct {
 type Dependency struct {
 	Type   DependencyType
 	Target string
+	Lag    DurationSpec // 有符号延迟：正值为等待（lag），负值为提前（lead）；天单位按工作日计算
+
+	Critical bool // ResolveSchedule 计算：两端均为关键任务且无余量的驱动依赖
+}
+
+// Constraint 描述任务上的日期约束。
+type Constraint struct {
+	Type ConstraintType
+	Expr string // 原始日期表达式（可为相对日期）
+	Date time.Time
+}
+
+// Recurrence 描述重复任务规则：every <间隔> until <日期> 或 every <间隔> x<次数>。
+type Recurrence struct {
+	Interval     DurationSpec
+	Count        int       // 实例数上限，0 表示仅以 Until 截止
+	Until        time.Time // 最后实例开始日期（含）
+	UntilExpr    string
+	SkipExcluded bool // true 时跳过落在排除日的实例，否则顺延到下一工作日
+}
+
+// Occurrence 表示重复任务展开后的单次实例。
+type Occurrence struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Warning 描述排程中发现但不阻断渲染的问题。
+type Warning struct {
+	Kind    WarningKind
+	TaskIDs []string
+	Line    int
+	Date    time.Time // 相关日期，如截止日或约束日期
+	Message string
+
+	Resource string    // 过载警告对应的资源
+	End      time.Time // 区间类警告的最后一天（含）
+}
+
+func (w Warning) String() string {
+	return w.Message
 }
 
 // TodayMarker 控制今日标记。
@@ -49,6 +121,7 @@ type TodayMarker struct {
 	Enabled bool
 	Date    time.Time
 	HasDate bool
+	Expr    string // 相对日期表达式（如 today-3d），ResolveSchedule 时求值
 }
 
 // TickInterval 控制轴刻度间隔。
@@ -58,13 +131,27 @@ type TickInterval struct {
 	Valid bool
 }
 
-// Calendar 记录日历规则。
-type Calendar struct {
-	Timezone       string
-	ExcludeWeekend bool
-	WeekendDays    []time.Weekday // 可自定义周末集合；为空时使用默认周末
-	ExcludeDates   []time.Time
-	IncludeDates   []time.Time
+// Calendar 等日历类型定义在公开的 calendar 包中，排程、绘制与库使用者共用同一套规则。
+type (
+	Calendar   = calendar.Calendar
+	WorkHours  = calendar.WorkHours
+	ClockRange = calendar.ClockRange
+	Holiday    = calendar.Holiday
+)
+
+// Resource 描述参与排程的资源（人员/团队）。
+type Resource struct {
+	Name     string
+	Capacity int // 容量百分比，默认 100
+	Line     int // 声明所在行，0 表示仅由任务引用
+}
+
+// ResourceLoad 描述资源在某个工作日的负荷。
+type ResourceLoad struct {
+	Resource string
+	Date     time.Time
+	Load     int      // 当日峰值并发占用（百分比）
+	TaskIDs  []string // 当日承担的任务
 }
 
 // Task 表示解析后的任务。
@@ -78,13 +165,42 @@ type Task struct {
 	IsMilestone  bool
 	IsVertical   bool
 	HasTime      bool
-	Progress     int // 0-100
+	Progress     int     // 0-100
+	Cost         float64 // cost= 预算，0 表示未指定
 	Resources    []string
+	Units        map[string]int // 资源占用百分比（`alice 50%`），未列出的资源按 100%
 	Dependencies []Dependency
+	Constraints  []Constraint
+	Recurrence   *Recurrence
+	Occurrences  []Occurrence // 重复任务的实例，Start/End 为整个序列的范围
+
+	DeadlineMissed bool      // 排程结束晚于 deadline
+	LevelStart     time.Time // 资源平衡设定的最早开始，零值表示未平衡
+	Critical       bool      // ResolveSchedule 计算：位于关键路径（总时差不大于 0）
+
+	// 时差分析（ResolveSchedule 计算）：Start/End 即最早开始/完成；
+	// 时差以工作日计，总时差为不推迟项目完成（或截止日）可延误的天数，自由时差为不推迟任何后继的天数
+	LateStart  time.Time
+	LateFinish time.Time
+	TotalSlack int
+	FreeSlack  int
+
+	ForecastLow  time.Time // 蒙特卡洛预测的 P50 日期（ApplyForecast 写入）
+	ForecastHigh time.Time // 蒙特卡洛预测的 P90 日期
+
+	BaselineStart time.Time // 基线起止（ApplyBaseline 写入），零值表示无基线
+	BaselineEnd   time.Time
+	Slipped       bool // 完成晚于基线
+
+	Reforecast   bool      // Reforecast 模式下按剩余工作量从状态日重排
+	PlannedStart time.Time // 重排前的计划起止
+	PlannedEnd   time.Time
 
 	StartExpr        string // 绝对日期或相对表达式
 	EndExpr          string
 	Duration         DurationSpec
+	Estimate         *Estimate    // 三点估算，Duration 取其最可能值
+	Effort           DurationSpec // effort 工作量，非零时 Duration 由 ResolveSchedule 按资源占用推算
 	DurationDays     int
 	DurationExplicit bool
 
@@ -97,9 +213,10 @@ type Task struct {
 	Column int
 }
 
-// Section 表示分组。
+// Section 表示分组。嵌套 section 的 Name 为完整路径（如 "Backend / API"），Path 为各级名称。
 type Section struct {
 	Name  string
+	Path  []string
 	Tasks []Task
 }
 
@@ -114,6 +231,19 @@ type Model struct {
 	Calendar   Calendar
 	Sections   []Section
 	Verticals  []Task
+	Warnings   []Warning // ResolveSchedule 产生的排程警告
+	Resources  []Resource
+	Loads      []ResourceLoad // ResolveSchedule 计算的资源每日负荷
+
+	Clock        time.Time // 渲染时钟：相对日期的参照时刻，零值表示当前时间
+	ExcludeExprs []string  // excludes 中的相对日期表达式，ResolveSchedule 时展开
+	IncludeExprs []string  // includes 中的相对日期表达式
+
+	ScheduleFromEnd bool      // scheduleFrom end：未指定日期的任务以项目完成日为终点尽量后排
+	ProjectEnd      time.Time // 倒排模式下的项目完成日（含）
+	ProjectEndExpr  string    // 项目完成日的相对日期表达式，ResolveSchedule 时求值
+	ProjectStart    time.Time // ResolveSchedule 计算：全部任务的最早开始，倒排时即项目最迟开始
+	Reforecast      bool      // 按状态日（今日标记）将落后任务的剩余工作从状态日起重排
 }
 
 // ParseError 携带行列信息的错误。
@@ -126,3 +256,58 @@ type ParseError struct {
 func (e ParseError) Error() string {
 	return e.Message
 }
+
+// String 返回任务行中的状态关键字，普通任务为 "normal"。
+func (s TaskStatus) String() string {
+	switch s {
+	case StatusDone:
+		return "done"
+	case StatusActive:
+		return "active"
+	case StatusCritical:
+		return "crit"
+	case StatusMilestone:
+		return "milestone"
+	default:
+		return "normal"
+	}
+}
+
+// String 以 mermaid 语法输出持续时间，如 3d、2w、1mo。
+func (d DurationSpec) String() string {
+	suffix := "d"
+	switch d.Unit {
+	case DurationHour:
+		suffix = "h"
+	case DurationMinute:
+		suffix = "m"
+	case DurationWeek:
+		suffix = "w"
+	case DurationMonth:
+		suffix = "mo"
+	}
+	return strconv.Itoa(d.Value) + suffix
+}
+
+// String 以 mermaid 语法输出依赖，如 "after a1 +2d"，便于导出与提示展示。
+func (d Dependency) String() string {
+	keyword := "after"
+	switch d.Type {
+	case DepBefore:
+		keyword = "before"
+	case DepStartStart:
+		keyword = "with"
+	case DepFinishFinish:
+		keyword = "finishwith"
+	case DepStartFinish:
+		keyword = "sf"
+	}
+	out := keyword + " " + d.Target
+	switch {
+	case d.Lag.Value > 0:
+		out += " +" + d.Lag.String()
+	case d.Lag.Value < 0:
+		out += " " + d.Lag.String()
+	}
+	return out
+}
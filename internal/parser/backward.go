@@ -0,0 +1,143 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// parseScheduleFrom 解析 `scheduleFrom start` 与 `scheduleFrom end <日期|相对日期>`。
+func parseScheduleFrom(expr string, lineNo int, model *Model) error {
+	fields := strings.Fields(expr)
+	if len(fields) == 0 {
+		return newParseError(lineNo, 1, "scheduleFrom needs start or end <date>")
+	}
+	switch strings.ToLower(fields[0]) {
+	case "start":
+		model.ScheduleFromEnd = false
+		model.ProjectEnd = time.Time{}
+		model.ProjectEndExpr = ""
+		return nil
+	case "end":
+	default:
+		return newParseError(lineNo, 1, fmt.Sprintf("scheduleFrom: unknown mode %q, want start or end", fields[0]))
+	}
+	dateExpr := strings.TrimSpace(expr[len(fields[0]):])
+	if dateExpr == "" {
+		return newParseError(lineNo, 1, "scheduleFrom end needs a project end date")
+	}
+	model.ScheduleFromEnd = true
+	if t, err := parseDate(dateExpr, model.DateFormat, model.Calendar.Timezone); err == nil {
+		model.ProjectEnd = t
+		return nil
+	}
+	if isRelativeDate(dateExpr, model.DateFormat) {
+		model.ProjectEndExpr = dateExpr
+		return nil
+	}
+	return newParseError(lineNo, 1, fmt.Sprintf("scheduleFrom end: invalid date %q", dateExpr))
+}
+
+// scheduleBackward 在 scheduleFrom end 模式下将可移动的任务尽量后排：以项目完成日为终点
+// 沿依赖图反推最迟开始，显式开始、mustStartOn、重复任务与重排的落后任务保持前推结果不动。
+// noLaterThan 限制后排的上限；固定任务或 noEarlierThan 使后继无法按最迟时间安排时，顺推到最早可行位置并给出警告。
+func scheduleBackward(m *Model, loc *time.Location) {
+	if !m.ScheduleFromEnd || m.ProjectEnd.IsZero() {
+		return
+	}
+	net := buildNetwork(m, loc)
+	if net == nil {
+		return
+	}
+	for i := range net.nodes {
+		t := net.nodes[i].task
+		net.nodes[i].pinned = t.HasStart || t.Reforecast || !levelable(t)
+	}
+	net.backward(m, loc)
+
+	in := make([][]int, len(net.nodes))
+	for ei, e := range net.edges {
+		in[e.to] = append(in[e.to], ei)
+	}
+	for _, i := range net.order {
+		n := &net.nodes[i]
+		if n.pinned {
+			continue
+		}
+		dur := n.ef - n.es
+		es := n.ls
+		for _, c := range n.task.Constraints {
+			if c.Type != ConstraintNoLaterThan || c.Date.IsZero() {
+				continue
+			}
+			if b := m.Calendar.WorkingDaysBetween(net.base, constraintDay(c, loc)); b < es {
+				es = b
+			}
+		}
+		for _, ei := range in[i] {
+			if b := predecessorBound(net.edges[ei], net.nodes[net.edges[ei].from], dur); b > es {
+				es = b
+			}
+		}
+		for _, c := range n.task.Constraints {
+			if c.Type != ConstraintNoEarlierThan || c.Date.IsZero() {
+				continue
+			}
+			if b := m.Calendar.WorkingDaysBetween(net.base, constraintDay(c, loc)); b > es {
+				es = b
+			}
+		}
+		n.es, n.ef = es, es+dur
+
+		t := n.task
+		start := atClock(workingDayAt(net.base, es, m.Calendar), t.Start.In(loc))
+		if usesWorkHours(t.Duration, m.Calendar) {
+			start = m.Calendar.NextWorkingTime(start)
+		}
+		t.Start = start
+		t.End, t.DurationDays = scheduleEnd(start, t.Duration, m.Calendar)
+	}
+
+	projectEnd := calendarDay(m.ProjectEnd, loc)
+	for _, i := range net.order {
+		if n := net.nodes[i]; n.ef > net.end {
+			m.Warnings = append(m.Warnings, Warning{
+				Kind:    WarningProjectEndMissed,
+				TaskIDs: []string{n.task.ID},
+				Line:    n.task.Line,
+				Date:    projectEnd,
+				Message: fmt.Sprintf("task %s finishes %s, after the project end %s", n.task.ID, formatDay(n.task.End), formatDay(projectEnd)),
+			})
+		}
+	}
+}
+
+// predecessorBound 返回依赖边对后继最早开始（工作日索引）的下限，dur 为后继占用的工作日数。
+func predecessorBound(e netEdge, from netNode, dur int) int {
+	switch e.typ {
+	case DepStartStart:
+		return from.es + e.lag
+	case DepFinishFinish:
+		return from.ef + e.lag - dur
+	case DepStartFinish:
+		return from.es + e.lag - dur
+	default:
+		return from.ef + e.lag
+	}
+}
+
+// earliestStart 返回全部任务（含重复实例）中最早的开始时刻。
+func earliestStart(m Model) time.Time {
+	var first time.Time
+	for _, sec := range m.Sections {
+		for _, t := range sec.Tasks {
+			if t.IsVertical || t.Start.IsZero() {
+				continue
+			}
+			if first.IsZero() || t.Start.Before(first) {
+				first = t.Start
+			}
+		}
+	}
+	return first
+}
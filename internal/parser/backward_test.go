@@ -0,0 +1,106 @@
+package parser
+
+import "testing"
+
+func TestSchedule_FromEnd(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+scheduleFrom end 2025-03-21
+section Build
+Design :d1, 3d
+Code   :c1, after d1, 5d
+Review :r1, 2025-03-03, 2d
+section Release
+Ship   :s1, after c1, 2d
+Docs   :o1, 2d
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	// 反推链路 d1→c1→s1→o1（o1 顺接 s1），o1 须在 03-21（周五）完成
+	want := map[string][2]string{
+		"d1": {"2025-03-06", "2025-03-10"},
+		"c1": {"2025-03-11", "2025-03-17"},
+		"r1": {"2025-03-03", "2025-03-04"}, // 显式开始保持不动
+		"s1": {"2025-03-18", "2025-03-19"},
+		"o1": {"2025-03-20", "2025-03-21"},
+	}
+	for _, sec := range m.Sections {
+		for _, task := range sec.Tasks {
+			w := want[task.ID]
+			if got := [2]string{task.Start.Format("2006-01-02"), task.End.Format("2006-01-02")}; got != w {
+				t.Fatalf("%s: expected %v, got %v", task.ID, w, got)
+			}
+		}
+	}
+	if got := m.ProjectStart.Format("2006-01-02"); got != "2025-03-03" {
+		t.Fatalf("expected project start 2025-03-03, got %s", got)
+	}
+	if len(m.Warnings) != 0 {
+		t.Fatalf("expected no warnings, got %v", m.Warnings)
+	}
+}
+
+func TestSchedule_FromEndMissed(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+scheduleFrom end 2025-03-05
+section A
+Fixed :f1, 2025-03-03, 3d
+Next  :n1, after f1, 2d
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	// 固定任务占满到 03-05，后继只能顺推并给出警告
+	n1 := m.Sections[0].Tasks[1]
+	if got := n1.Start.Format("2006-01-02"); got != "2025-03-06" {
+		t.Fatalf("expected n1 to start 2025-03-06, got %s", got)
+	}
+	if len(m.Warnings) != 1 || m.Warnings[0].Kind != WarningProjectEndMissed || m.Warnings[0].TaskIDs[0] != "n1" {
+		t.Fatalf("expected project end warning for n1, got %v", m.Warnings)
+	}
+}
+
+func TestSchedule_FromEndTimezone(t *testing.T) {
+	// 项目完成日按图表时区解析，UTC 以西的时区不应提前一天；timezone 写在 scheduleFrom 之后亦然
+	for _, header := range []string{
+		"timezone America/New_York\nscheduleFrom end 2025-06-30",
+		"scheduleFrom end 2025-06-30\ntimezone America/New_York",
+	} {
+		m, err := Parse("gantt\ndateFormat YYYY-MM-DD\nexcludes weekends\n" + header + "\nsection A\nBuild :b1, 3d\nShip :s1, after b1, 2d\n")
+		if err != nil {
+			t.Fatalf("parse failed: %v", err)
+		}
+		m, err = ResolveSchedule(m)
+		if err != nil {
+			t.Fatalf("schedule failed: %v", err)
+		}
+		ship := m.Sections[0].Tasks[1]
+		if got := formatDay(ship.End); got != "2025-06-30" {
+			t.Fatalf("%q: expected s1 to finish on 2025-06-30, got %s", header, got)
+		}
+		if len(m.Warnings) != 0 {
+			t.Fatalf("%q: expected no warnings, got %v", header, m.Warnings)
+		}
+	}
+}
+
+func TestParse_ScheduleFromInvalid(t *testing.T) {
+	for _, line := range []string{"scheduleFrom", "scheduleFrom middle", "scheduleFrom end", "scheduleFrom end 2025-13-45"} {
+		if _, err := Parse("gantt\n" + line + "\nTask :a1, 2025-03-03, 1d"); err == nil {
+			t.Fatalf("expected error for %q", line)
+		}
+	}
+}
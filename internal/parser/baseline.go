@@ -0,0 +1,80 @@
+package parser
+
+import "time"
+
+// BaselineTask 为基线（冻结的计划）中单个任务的起止。
+type BaselineTask struct {
+	ID    string
+	Name  string
+	Start time.Time
+	End   time.Time
+}
+
+// Variance 为任务相对基线的偏差，天数按工作日计，正值表示推迟。
+type Variance struct {
+	TaskID        string
+	Name          string
+	BaselineStart time.Time
+	BaselineEnd   time.Time
+	Start         time.Time
+	End           time.Time
+	StartDays     int
+	FinishDays    int
+	Slipped       bool // 完成晚于基线
+	Added         bool // 基线中不存在的任务
+	Removed       bool // 仅存在于基线中的任务
+}
+
+// ApplyBaseline 按任务 ID 将基线起止写入已排程模型并返回偏差列表（按源中顺序，基线独有的任务排在最后）。
+func ApplyBaseline(m *Model, baseline []BaselineTask) []Variance {
+	loc := m.Calendar.Location()
+	byID := make(map[string]BaselineTask, len(baseline))
+	for _, b := range baseline {
+		byID[b.ID] = b
+	}
+	seen := make(map[string]bool)
+	var out []Variance
+	for si := range m.Sections {
+		for ti := range m.Sections[si].Tasks {
+			t := &m.Sections[si].Tasks[ti]
+			if t.IsVertical || t.Start.IsZero() {
+				continue
+			}
+			v := Variance{TaskID: t.ID, Name: t.Name, Start: t.Start, End: t.End}
+			b, ok := byID[t.ID]
+			if !ok {
+				v.Added = true
+				out = append(out, v)
+				continue
+			}
+			seen[t.ID] = true
+			t.BaselineStart, t.BaselineEnd = b.Start, b.End
+			v.BaselineStart, v.BaselineEnd = b.Start, b.End
+			v.StartDays = m.Calendar.WorkingDaysBetween(dayStart(b.Start, loc), dayStart(t.Start, loc))
+			v.FinishDays = m.Calendar.WorkingDaysBetween(dayStart(b.End, loc), dayStart(t.End, loc))
+			v.Slipped = t.End.After(b.End)
+			t.Slipped = v.Slipped
+			out = append(out, v)
+		}
+	}
+	for _, b := range baseline {
+		if !seen[b.ID] {
+			out = append(out, Variance{TaskID: b.ID, Name: b.Name, BaselineStart: b.Start, BaselineEnd: b.End, Removed: true})
+		}
+	}
+	return out
+}
+
+// BaselineOf 提取已排程模型的任务起止作为基线。
+func BaselineOf(m Model) []BaselineTask {
+	var out []BaselineTask
+	for _, sec := range m.Sections {
+		for _, t := range sec.Tasks {
+			if t.IsVertical || t.Start.IsZero() {
+				continue
+			}
+			out = append(out, BaselineTask{ID: t.ID, Name: t.Name, Start: t.Start, End: t.End})
+		}
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package parser
+
+import "testing"
+
+func TestApplyBaseline(t *testing.T) {
+	resolve := func(src string) Model {
+		m, err := Parse(src)
+		if err != nil {
+			t.Fatalf("parse failed: %v", err)
+		}
+		m, err = ResolveSchedule(m)
+		if err != nil {
+			t.Fatalf("schedule failed: %v", err)
+		}
+		return m
+	}
+	base := resolve(`gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+section A
+Design :d1, 2025-03-03, 3d
+Build  :b1, after d1, 5d
+Docs   :o1, 2025-03-03, 2d
+`)
+	cur := resolve(`gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+section A
+Design :d1, 2025-03-03, 5d
+Build  :b1, after d1, 5d
+Review :r1, 2025-03-04, 1d
+`)
+	vs := ApplyBaseline(&cur, BaselineOf(base))
+	if len(vs) != 4 {
+		t.Fatalf("expected 4 variances, got %+v", vs)
+	}
+	// d1 延长 2 个工作日，b1 随之顺延（开始跨过周末）
+	if d1 := vs[0]; d1.StartDays != 0 || d1.FinishDays != 2 || !d1.Slipped {
+		t.Fatalf("unexpected d1 variance: %+v", d1)
+	}
+	if b1 := vs[1]; b1.StartDays != 2 || b1.FinishDays != 2 || !b1.Slipped {
+		t.Fatalf("unexpected b1 variance: %+v", b1)
+	}
+	if !vs[2].Added || vs[2].TaskID != "r1" || !vs[3].Removed || vs[3].TaskID != "o1" || vs[3].Name != "Docs" {
+		t.Fatalf("expected r1 added and o1 removed, got %+v", vs[2:])
+	}
+	if b1 := cur.Sections[0].Tasks[1]; !b1.Slipped || b1.BaselineStart.Format("2006-01-02") != "2025-03-06" {
+		t.Fatalf("expected baseline written into model, got %+v", b1)
+	}
+}
@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"fmt"
+	"time"
+)
+
+// applyStartConstraints 按 noEarlierThan/mustStartOn 及资源平衡的推迟调整起点。
+// depStart 为依赖推导出的最早开始，mustStartOn 早于它时记录警告。
+func applyStartConstraints(t *Task, start, depStart time.Time, loc *time.Location, warnings *[]Warning) time.Time {
+	if t.LevelStart.After(start) {
+		start = t.LevelStart
+	}
+	for _, c := range t.Constraints {
+		if c.Type != ConstraintNoEarlierThan || c.Date.IsZero() {
+			continue
+		}
+		if day := constraintDay(c, loc); day.After(start) {
+			start = day
+		}
+	}
+	for _, c := range t.Constraints {
+		if c.Type != ConstraintMustStartOn || c.Date.IsZero() {
+			continue
+		}
+		day := constraintDay(c, loc)
+		if depStart.After(day) {
+			*warnings = append(*warnings, Warning{
+				Kind:    WarningConstraintViolated,
+				TaskIDs: []string{t.ID},
+				Line:    t.Line,
+				Date:    day,
+				Message: fmt.Sprintf("task %s must start on %s but its dependencies allow %s at the earliest", t.ID, formatDay(day), formatDay(depStart)),
+			})
+		}
+		start = day
+	}
+	return start
+}
+
+// checkConstraints 在排程完成后检查 deadline 与 noLaterThan，并标记逾期任务。
+func checkConstraints(m *Model, loc *time.Location) {
+	for si := range m.Sections {
+		for ti := range m.Sections[si].Tasks {
+			t := &m.Sections[si].Tasks[ti]
+			for _, c := range t.Constraints {
+				if c.Date.IsZero() {
+					continue
+				}
+				day := constraintDay(c, loc)
+				switch c.Type {
+				case ConstraintDeadline:
+					if t.End.Before(startOfNextDay(day)) {
+						continue
+					}
+					t.DeadlineMissed = true
+					m.Warnings = append(m.Warnings, Warning{
+						Kind:    WarningDeadlineMissed,
+						TaskIDs: []string{t.ID},
+						Line:    t.Line,
+						Date:    day,
+						Message: fmt.Sprintf("task %s misses deadline %s (ends %s)", t.ID, formatDay(day), formatDay(t.End)),
+					})
+				case ConstraintNoLaterThan:
+					if t.Start.Before(startOfNextDay(day)) {
+						continue
+					}
+					m.Warnings = append(m.Warnings, Warning{
+						Kind:    WarningConstraintViolated,
+						TaskIDs: []string{t.ID},
+						Line:    t.Line,
+						Date:    day,
+						Message: fmt.Sprintf("task %s should start no later than %s but starts %s", t.ID, formatDay(day), formatDay(t.Start)),
+					})
+				}
+			}
+		}
+	}
+}
+
+// Deadline 返回任务的截止日（若有多个取最早）。
+func (t Task) Deadline() (time.Time, bool) {
+	var deadline time.Time
+	for _, c := range t.Constraints {
+		if c.Type != ConstraintDeadline || c.Date.IsZero() {
+			continue
+		}
+		if deadline.IsZero() || c.Date.Before(deadline) {
+			deadline = c.Date
+		}
+	}
+	return deadline, !deadline.IsZero()
+}
+
+func constraintDay(c Constraint, loc *time.Location) time.Time {
+	return time.Date(c.Date.Year(), c.Date.Month(), c.Date.Day(), 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,98 @@
+package parser
+
+import "testing"
+
+func TestSchedule_ConstraintsAndDeadlines(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+section S
+Order :o1, 2025-02-24, 3d
+Assemble :a1, after o1, 5d, noEarlierThan 2025-03-03, deadline 2025-03-05
+Show :s1, after o1, mustStartOn 2025-02-25, 1d
+Demo :d1, after a1, 1d, deadline 2025-03-20
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	tasks := m.Sections[0].Tasks
+	if got := formatDay(tasks[1].Start); got != "2025-03-03" {
+		t.Fatalf("noEarlierThan start expected 2025-03-03, got %s", got)
+	}
+	if !tasks[1].DeadlineMissed || tasks[3].DeadlineMissed {
+		t.Fatalf("unexpected deadline flags: a1=%v d1=%v", tasks[1].DeadlineMissed, tasks[3].DeadlineMissed)
+	}
+	if got := formatDay(tasks[2].Start); got != "2025-02-25" {
+		t.Fatalf("mustStartOn start expected 2025-02-25, got %s", got)
+	}
+	if len(m.Warnings) != 2 {
+		t.Fatalf("expected deadline and mustStartOn warnings, got %v", m.Warnings)
+	}
+	kinds := map[WarningKind]bool{}
+	for _, w := range m.Warnings {
+		kinds[w.Kind] = true
+	}
+	if !kinds[WarningDeadlineMissed] || !kinds[WarningConstraintViolated] {
+		t.Fatalf("unexpected warning kinds: %v", m.Warnings)
+	}
+}
+
+func TestSchedule_NoLaterThan(t *testing.T) {
+	// 前推：依赖使任务晚于 noLaterThan 时不提前，仅给出警告
+	src := `gantt
+dateFormat YYYY-MM-DD
+section S
+Order :o1, 2025-02-24, 3d
+Pack :p1, after o1, 2d, noLaterThan 2025-02-25
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if got := formatDay(m.Sections[0].Tasks[1].Start); got != "2025-02-27" {
+		t.Fatalf("noLaterThan must not break dependencies, expected 2025-02-27, got %s", got)
+	}
+	if len(m.Warnings) != 1 || m.Warnings[0].Kind != WarningConstraintViolated {
+		t.Fatalf("expected a constraint warning, got %v", m.Warnings)
+	}
+
+	// 倒排：noLaterThan 限制后排的上限
+	src = `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+scheduleFrom end 2025-03-21
+section A
+Prep :p1, 2d, noLaterThan 2025-03-10
+section B
+Ship :s1, 2d
+`
+	if m, err = Parse(src); err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if m, err = ResolveSchedule(m); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if got := formatDay(m.Sections[0].Tasks[0].Start); got != "2025-03-10" {
+		t.Fatalf("expected backward start capped at 2025-03-10, got %s", got)
+	}
+	if got := formatDay(m.Sections[1].Tasks[0].End); got != "2025-03-21" {
+		t.Fatalf("expected s1 to finish on the project end, got %s", got)
+	}
+	if len(m.Warnings) != 0 {
+		t.Fatalf("expected no warnings, got %v", m.Warnings)
+	}
+}
+
+func TestParse_InvalidConstraintDate(t *testing.T) {
+	if _, err := Parse("gantt\nTask :t1, 1d, deadline someday\n"); err == nil {
+		t.Fatalf("expected error for invalid deadline")
+	}
+}
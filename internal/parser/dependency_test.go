@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSchedule_DependencyTypes(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+section Dev
+Develop :dev, 2025-01-06, 5d
+Release :rel, 2025-01-13, 3d
+section QA
+Testing :qa, with dev +1d, 2d
+Docs :doc, finishwith rel, 2d
+section Handoff
+Handover :ho, sf rel, 1d
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	qa := m.Sections[1].Tasks[0]
+	if len(qa.Dependencies) != 1 || qa.Dependencies[0].Type != DepStartStart {
+		t.Fatalf("expected start-to-start dependency, got %+v", qa.Dependencies)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	want := map[string][2]string{
+		"qa":  {"2025-01-07", "2025-01-08"},
+		"doc": {"2025-01-14", "2025-01-15"},
+		// 独立 section 的首个任务不顺接前序任务，只由 sf 决定：在 rel 开始（01-13）前完成
+		"ho": {"2025-01-12", "2025-01-12"},
+	}
+	for _, sec := range m.Sections[1:] {
+		for _, task := range sec.Tasks {
+			got := [2]string{formatDay(task.Start), formatDay(task.End)}
+			if got != want[task.ID] {
+				t.Fatalf("task %s expected %v, got %v", task.ID, want[task.ID], got)
+			}
+		}
+	}
+}
+
+func TestSchedule_DependencyTypesUnsatisfiable(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+section S
+Release :rel, 2025-01-13, 3d
+Docs :doc, 2025-01-06, 2025-01-08, ff rel
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	_, err = ResolveSchedule(m)
+	var perr ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected positioned error, got %v", err)
+	}
+	if perr.Line != 5 || !strings.Contains(perr.Message, "finishwith rel") {
+		t.Fatalf("unexpected error %+v", perr)
+	}
+
+	// 显式开始加工期同样保持开始不动，FF 无法满足时报错而不是顺延
+	m, err = Parse("gantt\ndateFormat YYYY-MM-DD\nsection S\nAlpha :a1, 2025-01-02, 7d\nBeta :b1, 2025-01-02, ff a1, 1d\n")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	_, err = ResolveSchedule(m)
+	if !errors.As(err, &perr) || perr.Line != 5 || !strings.Contains(perr.Message, "finishwith a1") {
+		t.Fatalf("expected positioned error for explicit start, got %v", err)
+	}
+}
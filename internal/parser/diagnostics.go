@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	maxSuggestions     = 3
+	suggestionDivisor  = 3 // 可接受的编辑距离为目标长度的 1/3（至少 1）
+	minSuggestDistance = 1
+)
+
+// DiagnosticsError 汇总一次检查中发现的多个带位置的问题，Unwrap 返回各条 ParseError。
+type DiagnosticsError struct {
+	Errors []ParseError
+}
+
+func (e DiagnosticsError) Error() string {
+	lines := make([]string, 0, len(e.Errors))
+	for _, pe := range e.Errors {
+		lines = append(lines, fmt.Sprintf("line %d: %s", pe.Line, pe.Message))
+	}
+	return fmt.Sprintf("%d dependency problems:\n%s", len(e.Errors), strings.Join(lines, "\n"))
+}
+
+func (e DiagnosticsError) Unwrap() []error {
+	out := make([]error, len(e.Errors))
+	for i, pe := range e.Errors {
+		out[i] = pe
+	}
+	return out
+}
+
+// checkDependencies 一次性检查全部依赖：自依赖与找不到的目标（附带相近的任务 ID 或分组名建议）。
+// 仅一个问题时返回 ParseError，多个时返回 DiagnosticsError。
+func checkDependencies(m Model, taskMap map[string]*Task) error {
+	var candidates []string
+	for id := range taskMap {
+		candidates = append(candidates, id)
+	}
+	for _, sec := range m.Sections {
+		if sec.Name != "" {
+			candidates = append(candidates, groupKey(sec.Name))
+		}
+	}
+	sort.Strings(candidates)
+
+	var errs []ParseError
+	for _, sec := range m.Sections {
+		for _, t := range sec.Tasks {
+			if t.IsVertical {
+				continue
+			}
+			for _, dep := range t.Dependencies {
+				if dep.Target == t.ID {
+					errs = append(errs, ParseError{Line: t.Line, Column: t.Column, Message: fmt.Sprintf("task %s depends on itself (%s)", t.ID, dep)})
+					continue
+				}
+				if _, ok := taskMap[dep.Target]; ok {
+					continue
+				}
+				msg := fmt.Sprintf("dependency not found: %s", dep.Target)
+				if s := suggest(dep.Target, candidates); len(s) > 0 {
+					msg += fmt.Sprintf(" (did you mean %s?)", strings.Join(s, ", "))
+				}
+				errs = append(errs, ParseError{Line: t.Line, Column: t.Column, Message: msg})
+			}
+		}
+	}
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return DiagnosticsError{Errors: errs}
+	}
+}
+
+// cycleError 以完整路径报告循环依赖：stack 为当前解析链，t 为再次遇到的任务。
+func cycleError(stack []*Task, t *Task) error {
+	i := slices.Index(stack, t)
+	if i < 0 {
+		return ParseError{Line: t.Line, Column: t.Column, Message: fmt.Sprintf("circular dependency around %s", t.ID)}
+	}
+	parts := make([]string, 0, len(stack)-i+1)
+	for _, p := range stack[i:] {
+		parts = append(parts, fmt.Sprintf("%s (line %d)", p.ID, p.Line))
+	}
+	parts = append(parts, fmt.Sprintf("%s (line %d)", t.ID, t.Line))
+	return ParseError{Line: t.Line, Column: t.Column, Message: "circular dependency: " + strings.Join(parts, " -> ")}
+}
+
+// duplicateIDWarning 描述重复 ID 被改名的任务。
+func duplicateIDWarning(first, dup *Task, renamed string) Warning {
+	return Warning{
+		Kind:    WarningDuplicateID,
+		TaskIDs: []string{first.ID, renamed},
+		Line:    dup.Line,
+		Message: fmt.Sprintf("duplicate task id %s on line %d renamed to %s (first defined on line %d)", first.ID, dup.Line, renamed, first.Line),
+	}
+}
+
+// suggest 返回与 target 编辑距离最近的候选（不区分大小写），最多 maxSuggestions 个。
+func suggest(target string, candidates []string) []string {
+	limit := max(len([]rune(target))/suggestionDivisor, minSuggestDistance)
+	type scored struct {
+		name string
+		dist int
+	}
+	var found []scored
+	for _, c := range candidates {
+		if d := editDistance(strings.ToLower(target), strings.ToLower(c)); d <= limit {
+			found = append(found, scored{c, d})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].dist < found[j].dist })
+	var out []string
+	for _, f := range found[:min(len(found), maxSuggestions)] {
+		out = append(out, f.name)
+	}
+	return out
+}
+
+// editDistance 计算两个字符串的 Levenshtein 距离（按 rune）。
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
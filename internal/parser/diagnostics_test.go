@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResolveSchedule_CyclePath(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+section S
+A :a1, after c3, 1d
+B :b2, after a1, 1d
+C :c3, after b2, 1d
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	_, err = ResolveSchedule(m)
+	want := "circular dependency: a1 (line 4) -> c3 (line 6) -> b2 (line 5) -> a1 (line 4)"
+	if err == nil || err.Error() != want {
+		t.Fatalf("expected %q, got %v", want, err)
+	}
+}
+
+func TestResolveSchedule_AllMissingDependencies(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+section Build
+Design :design, 2025-03-03, 2d
+Code :code, after desgin, 3d
+Test :test, after code qa, 2d
+Loop :loop, after loop, 1d
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	_, err = ResolveSchedule(m)
+	var diag DiagnosticsError
+	if !errors.As(err, &diag) || len(diag.Errors) != 3 {
+		t.Fatalf("expected 3 diagnostics, got %v", err)
+	}
+	if d := diag.Errors[0]; d.Line != 5 || d.Message != "dependency not found: desgin (did you mean design?)" {
+		t.Fatalf("unexpected first diagnostic: %+v", d)
+	}
+	if d := diag.Errors[1]; d.Line != 6 || d.Message != "dependency not found: qa" {
+		t.Fatalf("unexpected second diagnostic: %+v", d)
+	}
+	if d := diag.Errors[2]; d.Line != 7 || !strings.Contains(d.Message, "loop depends on itself") {
+		t.Fatalf("unexpected self-dependency diagnostic: %+v", d)
+	}
+	var perr ParseError
+	if !errors.As(err, &perr) || perr.Line != 5 {
+		t.Fatalf("expected unwrap to the first positioned error, got %+v", perr)
+	}
+	if !strings.Contains(err.Error(), "line 6: dependency not found: qa") {
+		t.Fatalf("expected line numbers in summary, got %q", err.Error())
+	}
+}
+
+func TestResolveSchedule_DuplicateIDWarning(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+section S
+A :a1, 2025-03-03, 1d
+B :a1, 2025-03-04, 1d
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if len(m.Warnings) != 1 || m.Warnings[0].Kind != WarningDuplicateID || m.Warnings[0].Line != 5 ||
+		m.Warnings[0].Message != "duplicate task id a1 on line 5 renamed to a1_1 (first defined on line 4)" {
+		t.Fatalf("unexpected warnings: %+v", m.Warnings)
+	}
+}
@@ -0,0 +1,156 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const costPrefix = "cost="
+
+// EVMetrics 为挣值指标：BAC 为完工预算，PV/EV 为截至状态日的计划值与挣值，
+// SV = EV - PV，SPI = EV / PV（PV 为 0 时记 0）。
+type EVMetrics struct {
+	BAC float64
+	PV  float64
+	EV  float64
+	SV  float64
+	SPI float64
+}
+
+// TaskEarnedValue 为单个任务的挣值。
+type TaskEarnedValue struct {
+	ID       string
+	Name     string
+	Section  string
+	Planned  float64 // 截至状态日计划完成比例（0-1）
+	Complete float64 // 实际完成比例（进度或 done 状态）
+	EVMetrics
+}
+
+// SectionEarnedValue 为 section 汇总的挣值。
+type SectionEarnedValue struct {
+	Name string
+	EVMetrics
+}
+
+// EarnedValueReport 为挣值分析结果。
+type EarnedValueReport struct {
+	StatusDate time.Time
+	CostBased  bool // 按 cost= 加权；否则按工期（工作日）加权
+	Tasks      []TaskEarnedValue
+	Sections   []SectionEarnedValue
+	Project    EVMetrics
+}
+
+// parseCost 解析任务行中的 `cost=<金额>`。
+func parseCost(field string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(field[len(costPrefix):]), 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid cost %q", field)
+	}
+	return v, nil
+}
+
+// StatusDate 返回挣值分析的状态日：todayMarker 指定的日期，否则为渲染时钟当日。
+func StatusDate(m Model) time.Time {
+	loc := m.Calendar.Location()
+	if m.Today.HasDate {
+		return dayStart(m.Today.Date, loc)
+	}
+	return dayStart(renderClock(m, loc), loc)
+}
+
+// EarnedValue 以已排程的模型计算截至 status（含当日）的挣值。任一任务写了 cost= 时按成本加权，
+// 未写 cost 的任务预算为 0；否则以工作日工期为预算。计划完成比例按工作日（时间型任务按时长）线性累计。
+func EarnedValue(m Model, status time.Time) EarnedValueReport {
+	loc := m.Calendar.Location()
+	status = dayStart(status, loc)
+	cutoff := startOfNextDay(status)
+	rep := EarnedValueReport{StatusDate: status}
+	for _, sec := range m.Sections {
+		for _, t := range sec.Tasks {
+			if t.Cost > 0 {
+				rep.CostBased = true
+			}
+		}
+	}
+	for _, sec := range m.Sections {
+		section := SectionEarnedValue{Name: sec.Name}
+		for _, t := range sec.Tasks {
+			if t.IsVertical || t.Start.IsZero() {
+				continue
+			}
+			tv := TaskEarnedValue{
+				ID:       t.ID,
+				Name:     t.Name,
+				Section:  sec.Name,
+				Planned:  plannedFraction(&t, cutoff, m.Calendar, loc),
+				Complete: float64(t.Progress) / maxProgressPercent,
+			}
+			if t.Status == StatusDone {
+				tv.Complete = 1
+			}
+			tv.BAC = t.Cost
+			if !rep.CostBased {
+				tv.BAC = taskWorkDays(&t, m.Calendar, loc)
+			}
+			tv.PV = tv.BAC * tv.Planned
+			tv.EV = tv.BAC * tv.Complete
+			tv.EVMetrics = tv.EVMetrics.finish()
+			rep.Tasks = append(rep.Tasks, tv)
+			section.EVMetrics = section.add(tv.EVMetrics)
+		}
+		section.EVMetrics = section.EVMetrics.finish()
+		rep.Sections = append(rep.Sections, section)
+		rep.Project = rep.Project.add(section.EVMetrics).finish()
+	}
+	return rep
+}
+
+func (e EVMetrics) add(o EVMetrics) EVMetrics {
+	e.BAC += o.BAC
+	e.PV += o.PV
+	e.EV += o.EV
+	return e
+}
+
+// finish 由 PV/EV 计算 SV 与 SPI。
+func (e EVMetrics) finish() EVMetrics {
+	e.SV = e.EV - e.PV
+	e.SPI = 0
+	if e.PV > 0 {
+		e.SPI = e.EV / e.PV
+	}
+	return e
+}
+
+// plannedFraction 返回截至 cutoff（不含）任务应完成的比例。
+func plannedFraction(t *Task, cutoff time.Time, cal Calendar, loc *time.Location) float64 {
+	if !t.Start.Before(cutoff) {
+		return 0
+	}
+	if !cutoff.Before(taskFinish(t)) {
+		return 1
+	}
+	if t.HasTime || t.Duration.Unit == DurationHour || t.Duration.Unit == DurationMinute {
+		return cutoff.Sub(t.Start).Hours() / taskFinish(t).Sub(t.Start).Hours()
+	}
+	total := taskWorkDays(t, cal, loc)
+	if total <= 0 {
+		return 1
+	}
+	return float64(cal.WorkingDaysBetween(dayStart(t.Start, loc), cutoff)) / total
+}
+
+// taskWorkDays 返回任务占用的工作日数，时间型任务按时长折算。
+func taskWorkDays(t *Task, cal Calendar, loc *time.Location) float64 {
+	if t.IsMilestone || t.Duration.Value == 0 {
+		return 0
+	}
+	if t.HasTime || t.Duration.Unit == DurationHour || t.Duration.Unit == DurationMinute {
+		return t.End.Sub(t.Start).Hours() / hoursPerDay
+	}
+	return float64(cal.WorkingDaysBetween(dayStart(t.Start, loc), startOfNextDay(t.End.In(loc))))
+}
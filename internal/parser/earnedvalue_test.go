@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEarnedValue(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+section Build
+Design :d1, 2025-03-03, 5d, done
+Code   :c1, after d1, 10d, 20%
+section Release
+Ship   :milestone, s1, after c1, 0d
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	// 状态日 03-14（周五）：d1 计划完成；c1 计划完成 5/10，实际 20%
+	rep := EarnedValue(m, time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC))
+	if rep.CostBased || len(rep.Tasks) != 3 || len(rep.Sections) != 2 {
+		t.Fatalf("unexpected report shape: %+v", rep)
+	}
+	c1 := rep.Tasks[1]
+	if c1.BAC != 10 || c1.PV != 5 || c1.EV != 2 || c1.SV != -3 {
+		t.Fatalf("unexpected c1 metrics: %+v", c1)
+	}
+	p := rep.Project
+	if p.BAC != 15 || p.PV != 10 || p.EV != 7 || math.Abs(p.SPI-0.7) > 1e-9 {
+		t.Fatalf("unexpected project metrics: %+v", p)
+	}
+	if rep.Sections[1].PV != 0 || rep.Sections[1].SPI != 0 {
+		t.Fatalf("milestone section should have no value: %+v", rep.Sections[1])
+	}
+}
+
+func TestEarnedValue_CostWeighted(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+todayMarker 2025-03-04
+section A
+Cheap :a1, 2025-03-03, 4d, cost=100, 50%
+Dear  :b1, 2025-03-03, 2d, cost=900
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	rep := EarnedValue(m, StatusDate(m))
+	if !rep.CostBased || rep.StatusDate.Format("2006-01-02") != "2025-03-04" {
+		t.Fatalf("expected cost basis as of 2025-03-04, got %+v", rep)
+	}
+	// a1 计划 2/4、挣值 50；b1 计划全部完成、进度为 0
+	if p := rep.Project; p.BAC != 1000 || p.PV != 950 || p.EV != 50 {
+		t.Fatalf("unexpected project metrics: %+v", p)
+	}
+	if _, err := Parse("gantt\nTask :a1, 2025-03-03, 1d, cost=abc\n"); err == nil {
+		t.Fatalf("expected error for invalid cost")
+	}
+}
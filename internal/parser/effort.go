@@ -0,0 +1,54 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const effortPrefix = "effort "
+
+// assignmentRe 匹配任务行中的 `alice 50%`：资源名后跟占用百分比。
+var assignmentRe = regexp.MustCompile(`^(\S+)\s+([0-9]+)%$`)
+
+// parseEffort 解析 `effort 10d`，单位与持续时间相同。
+func parseEffort(field string) (DurationSpec, error) {
+	val := strings.TrimSpace(field[len(effortPrefix):])
+	if !looksLikeDuration(val) {
+		return DurationSpec{}, fmt.Errorf("invalid effort %q", field)
+	}
+	spec := parseDurationSpec(val)
+	if spec.Value <= 0 {
+		return DurationSpec{}, fmt.Errorf("invalid effort %q", field)
+	}
+	return spec, nil
+}
+
+// parseAssignment 解析资源占用 `alice 50%`；字段不是该形式时 ok 为 false。
+func parseAssignment(field string) (name string, units int, ok bool, err error) {
+	m := assignmentRe.FindStringSubmatch(field)
+	if m == nil {
+		return "", 0, false, nil
+	}
+	units, err = strconv.Atoi(m[2])
+	if err != nil || units <= 0 {
+		return "", 0, true, fmt.Errorf("invalid assignment units %q", field)
+	}
+	return m[1], units, true, nil
+}
+
+// effortDuration 由工作量与各资源占用推算工期：工期 = 工作量 ÷ 占用合计（未标注的资源按 100%，
+// 无资源时按一人全职），按工作量的单位向上取整。
+func effortDuration(t *Task) DurationSpec {
+	total := 0
+	for _, a := range taskAssignments(t) {
+		total += a.units
+	}
+	if total == 0 {
+		total = fullAllocation
+	}
+	value := int(math.Ceil(float64(t.Effort.Value) * fullAllocation / float64(total)))
+	return DurationSpec{Value: value, Unit: t.Effort.Unit}
+}
@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveSchedule_EffortDuration(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+section Build
+Solo  :e1, 2025-03-03, effort 10d, alice
+Pair  :e2, 2025-03-03, effort 10d, alice 50%, bob
+Crowd :e3, 2025-03-03, effort 10d, carol, dave
+Alone :e4, 2025-03-03, effort 3d
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	// 10d ÷ 150% 向上取整为 7d，两人全职减半为 5d
+	want := map[string]string{"e1": "10d", "e2": "7d", "e3": "5d", "e4": "3d"}
+	for _, task := range m.Sections[0].Tasks {
+		if got := task.Duration.String(); got != want[task.ID] || task.Effort.Value != 10 && task.ID != "e4" {
+			t.Fatalf("task %s: duration %s effort %s, want duration %s", task.ID, got, task.Effort, want[task.ID])
+		}
+	}
+	if e2 := m.Sections[0].Tasks[1]; e2.End.Format("2006-01-02") != "2025-03-11" || e2.Units["alice"] != 50 {
+		t.Fatalf("unexpected e2: end %s units %v", e2.End, e2.Units)
+	}
+	for _, l := range m.Loads {
+		if l.Resource == "bob" && l.Load != 100 {
+			t.Fatalf("bob should be fully allocated, got %+v", l)
+		}
+	}
+}
+
+func TestParse_EffortWithDuration(t *testing.T) {
+	_, err := Parse("gantt\nsection S\nA :a1, 2025-03-03, 5d, effort 10d\n")
+	if err == nil || !strings.Contains(err.Error(), "effort and duration cannot both be set") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if _, err := Parse("gantt\nsection S\nA :a1, 2025-03-03, effort 10d, alice 0%\n"); err == nil {
+		t.Fatalf("expected invalid assignment units error")
+	}
+}
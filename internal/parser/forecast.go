@@ -0,0 +1,254 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	estimatePoints        = 3    // 三点估算：乐观/最可能/悲观
+	defaultSimulationRuns = 1000 // SimulationOptions.Runs 缺省值
+	pertWeight            = 4    // beta-PERT 中最可能值的权重
+	simulationStream      = 0x9e3779b97f4a7c15
+)
+
+// 预测结果给出的分位点。
+const (
+	percentile50 = 0.50
+	percentile80 = 0.80
+	percentile90 = 0.90
+	percentile95 = 0.95
+)
+
+// Estimate 为三点估算 `3d/5d/10d`（乐观/最可能/悲观），三者单位相同；确定性排程使用最可能值。
+type Estimate struct {
+	Optimistic  DurationSpec
+	Likely      DurationSpec
+	Pessimistic DurationSpec
+}
+
+// Distribution 为蒙特卡洛模拟中工期的抽样分布。
+type Distribution int
+
+const (
+	DistributionPERT       Distribution = iota // beta-PERT：以最可能值为众数、权重为 4 的 beta 分布
+	DistributionTriangular                     // 三角分布
+)
+
+// SimulationOptions 控制蒙特卡洛模拟。
+type SimulationOptions struct {
+	Runs         int   // 模拟次数，0 表示 1000
+	Seed         int64 // 随机种子，相同种子得到相同结果
+	Distribution Distribution
+	Level        bool // 每次模拟后执行资源平衡
+}
+
+// MilestoneForecast 为单个里程碑（或项目完成）日期的分布。
+type MilestoneForecast struct {
+	ID      string
+	Name    string
+	Planned time.Time // 按最可能工期排程的日期
+	P50     time.Time
+	P80     time.Time
+	P90     time.Time
+	P95     time.Time
+}
+
+// Forecast 为蒙特卡洛模拟结果。
+type Forecast struct {
+	Runs       int
+	Milestones []MilestoneForecast // 按源中顺序
+	Finish     MilestoneForecast   // 项目完成（全部任务的最晚结束），ID 为空
+}
+
+// looksLikeEstimate 判断字段是否形如 `3d/5d/10d`。
+func looksLikeEstimate(field string) bool {
+	parts := strings.Split(field, "/")
+	if len(parts) != estimatePoints {
+		return false
+	}
+	for _, p := range parts {
+		if !looksLikeDuration(strings.TrimSpace(p)) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseEstimate 解析三点估算，要求单位一致且乐观 ≤ 最可能 ≤ 悲观。
+func parseEstimate(field string) (Estimate, error) {
+	parts := strings.Split(field, "/")
+	est := Estimate{
+		Optimistic:  parseDurationSpec(parts[0]),
+		Likely:      parseDurationSpec(parts[1]),
+		Pessimistic: parseDurationSpec(parts[2]),
+	}
+	if est.Optimistic.Unit != est.Likely.Unit || est.Likely.Unit != est.Pessimistic.Unit {
+		return Estimate{}, fmt.Errorf("three-point estimate %q must use a single unit", field)
+	}
+	if est.Optimistic.Value > est.Likely.Value || est.Likely.Value > est.Pessimistic.Value {
+		return Estimate{}, fmt.Errorf("three-point estimate %q must be ordered optimistic/likely/pessimistic", field)
+	}
+	return est, nil
+}
+
+// Simulate 对带三点估算的任务重新抽样工期并重复排程，统计各里程碑日期的分位点。
+// m 为尚未排程的模型，不会被修改；每次模拟前检查 ctx，取消后立即返回 ctx.Err()。
+func Simulate(ctx context.Context, m Model, opt SimulationOptions) (Forecast, error) {
+	runs := opt.Runs
+	if runs <= 0 {
+		runs = defaultSimulationRuns
+	}
+	resolve := ResolveSchedule
+	if opt.Level {
+		resolve = func(m Model) (Model, error) {
+			out, _, err := LevelResources(m)
+			return out, err
+		}
+	}
+	planned, err := resolve(cloneModel(m))
+	if err != nil {
+		return Forecast{}, err
+	}
+	fc := Forecast{Runs: runs, Finish: MilestoneForecast{Planned: latestEnd(planned)}}
+	index := make(map[string]int)
+	for _, sec := range planned.Sections {
+		for _, t := range sec.Tasks {
+			if isMilestone(t) {
+				index[t.ID] = len(fc.Milestones)
+				fc.Milestones = append(fc.Milestones, MilestoneForecast{ID: t.ID, Name: t.Name, Planned: t.Start})
+			}
+		}
+	}
+
+	rng := rand.New(rand.NewPCG(uint64(opt.Seed), simulationStream))
+	samples := make([][]time.Time, len(fc.Milestones))
+	finish := make([]time.Time, 0, runs)
+	for r := 0; r < runs; r++ {
+		if err := ctx.Err(); err != nil {
+			return Forecast{}, err
+		}
+		run := cloneModel(m)
+		for si := range run.Sections {
+			for ti := range run.Sections[si].Tasks {
+				t := &run.Sections[si].Tasks[ti]
+				if t.Estimate != nil {
+					t.Duration = sampleEstimate(*t.Estimate, opt.Distribution, rng)
+				}
+			}
+		}
+		out, err := resolve(run)
+		if err != nil {
+			return Forecast{}, fmt.Errorf("simulation run %d: %w", r+1, err)
+		}
+		for _, sec := range out.Sections {
+			for _, t := range sec.Tasks {
+				if k, ok := index[t.ID]; ok {
+					samples[k] = append(samples[k], t.Start)
+				}
+			}
+		}
+		finish = append(finish, latestEnd(out))
+	}
+	for i := range fc.Milestones {
+		fillPercentiles(&fc.Milestones[i], samples[i])
+	}
+	fillPercentiles(&fc.Finish, finish)
+	return fc, nil
+}
+
+// ApplyForecast 将各里程碑的 P50–P90 区间写入已排程模型，供绘制阴影带。
+func ApplyForecast(m *Model, fc Forecast) {
+	bands := make(map[string]MilestoneForecast, len(fc.Milestones))
+	for _, f := range fc.Milestones {
+		bands[f.ID] = f
+	}
+	for si := range m.Sections {
+		for ti := range m.Sections[si].Tasks {
+			t := &m.Sections[si].Tasks[ti]
+			if f, ok := bands[t.ID]; ok {
+				t.ForecastLow, t.ForecastHigh = f.P50, f.P90
+			}
+		}
+	}
+}
+
+// sampleEstimate 按分布抽取一次工期，取整到估算单位。
+func sampleEstimate(est Estimate, dist Distribution, rng *rand.Rand) DurationSpec {
+	a, c, b := float64(est.Optimistic.Value), float64(est.Likely.Value), float64(est.Pessimistic.Value)
+	if b <= a {
+		return est.Likely
+	}
+	var x float64
+	switch dist {
+	case DistributionTriangular:
+		u := rng.Float64()
+		if u < (c-a)/(b-a) {
+			x = a + math.Sqrt(u*(b-a)*(c-a))
+		} else {
+			x = b - math.Sqrt((1-u)*(b-a)*(b-c))
+		}
+	default:
+		alpha := 1 + pertWeight*(c-a)/(b-a)
+		beta := 1 + pertWeight*(b-c)/(b-a)
+		g1, g2 := sampleGamma(alpha, rng), sampleGamma(beta, rng)
+		x = a + (b-a)*g1/(g1+g2)
+	}
+	return DurationSpec{Value: int(math.Round(x)), Unit: est.Likely.Unit}
+}
+
+// sampleGamma 以 Marsaglia–Tsang 方法抽取 Gamma(shape, 1)，shape ≥ 1。
+func sampleGamma(shape float64, rng *rand.Rand) float64 {
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if math.Log(u) < x*x/2+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+// fillPercentiles 对样本排序并写入分位点（最近秩法）。
+func fillPercentiles(f *MilestoneForecast, samples []time.Time) {
+	if len(samples) == 0 {
+		return
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Before(samples[j]) })
+	at := func(p float64) time.Time {
+		idx := int(math.Ceil(p*float64(len(samples)))) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		return samples[idx]
+	}
+	f.P50, f.P80, f.P90, f.P95 = at(percentile50), at(percentile80), at(percentile90), at(percentile95)
+}
+
+func isMilestone(t Task) bool {
+	return !t.IsVertical && (t.IsMilestone || t.Duration.Value == 0)
+}
+
+// latestEnd 返回全部任务中最晚的结束时刻。
+func latestEnd(m Model) time.Time {
+	var last time.Time
+	for _, sec := range m.Sections {
+		for _, t := range sec.Tasks {
+			if !t.IsVertical && t.End.After(last) {
+				last = t.End
+			}
+		}
+	}
+	return last
+}
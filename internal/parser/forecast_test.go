@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse_ThreePointEstimate(t *testing.T) {
+	m, err := Parse("gantt\ndateFormat YYYY-MM-DD\nBuild :b1, 2025-03-03, 3d/5d/10d\n")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	task := m.Sections[0].Tasks[0]
+	if task.Estimate == nil || task.Estimate.Optimistic.Value != 3 || task.Estimate.Pessimistic.Value != 10 {
+		t.Fatalf("expected 3d/5d/10d estimate, got %+v", task.Estimate)
+	}
+	if task.Duration.Value != 5 {
+		t.Fatalf("expected likely duration 5d, got %v", task.Duration)
+	}
+	for _, field := range []string{"5d/3d/10d", "3d/5h/10d"} {
+		if _, err := Parse("gantt\nBuild :b1, 2025-03-03, " + field + "\n"); err == nil {
+			t.Fatalf("expected error for %q", field)
+		}
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+section A
+Build  :b1, 2025-03-03, 2d/4d/12d
+Test   :t1, after b1, 1d/2d/3d
+Launch :milestone, m1, after t1, 0d
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	for _, dist := range []Distribution{DistributionPERT, DistributionTriangular} {
+		opt := SimulationOptions{Runs: 500, Seed: 7, Distribution: dist}
+		fc, err := Simulate(t.Context(), m, opt)
+		if err != nil {
+			t.Fatalf("simulate failed: %v", err)
+		}
+		if len(fc.Milestones) != 1 || fc.Milestones[0].ID != "m1" {
+			t.Fatalf("expected milestone m1, got %+v", fc.Milestones)
+		}
+		f := fc.Milestones[0]
+		// 最可能工期下 m1 落在 03-09；右偏的估算使 P50 不早于计划且分位点单调
+		if got := f.Planned.Format("2006-01-02"); got != "2025-03-09" {
+			t.Fatalf("expected planned 2025-03-09, got %s", got)
+		}
+		if f.P50.Before(f.Planned) || f.P80.Before(f.P50) || f.P90.Before(f.P80) || f.P95.Before(f.P90) {
+			t.Fatalf("percentiles out of order: %+v", f)
+		}
+		if f.P95.After(time.Date(2025, 3, 18, 0, 0, 0, 0, time.UTC)) {
+			t.Fatalf("P95 beyond pessimistic bound: %s", f.P95)
+		}
+		again, _ := Simulate(t.Context(), m, opt)
+		if !again.Milestones[0].P80.Equal(f.P80) || !again.Finish.P95.Equal(fc.Finish.P95) {
+			t.Fatalf("expected identical results for the same seed")
+		}
+	}
+	if m.Sections[0].Tasks[0].Duration.Value != 4 {
+		t.Fatalf("simulation must not modify the input model")
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if _, err := Simulate(ctx, m, SimulationOptions{Runs: 1_000_000}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancelled simulation to stop, got %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -24,13 +25,19 @@ const (
 	minMatchesForDate     = 3
 	minDurationParts      = 2
 	percentDivisor        = 100.0
+	minLagLen             = 2 // 符号加至少一位数字，如 +2
 )
 
 var tickIntervalRe = regexp.MustCompile(`^([1-9][0-9]*)(millisecond|second|minute|hour|day|week|month)$`)
 
-// Parse 从字符串解析 Gantt。
-// nolint:gocyclo // 复杂度较高，后续按 refactor-design.md 拆分
+// Parse 从字符串解析 Gantt；excludes ics 的相对路径基于当前目录。
 func Parse(src string) (Model, error) {
+	return ParseWithOptions(src, ParseOptions{})
+}
+
+// ParseWithOptions 从字符串解析 Gantt，外部日历文件按 opt 读取。
+// nolint:gocyclo // 复杂度较高，后续按 refactor-design.md 拆分
+func ParseWithOptions(src string, opt ParseOptions) (Model, error) {
 	if strings.TrimSpace(src) == "" {
 		return Model{}, fmt.Errorf("source is empty")
 	}
@@ -75,6 +82,11 @@ func Parse(src string) (Model, error) {
 		case strings.HasPrefix(lower, "todaymarker"):
 			parseTodayMarker(strings.TrimSpace(line[len("todaymarker"):]), model.DateFormat, &model)
 			continue
+		case strings.HasPrefix(lower, "schedulefrom"):
+			if err := parseScheduleFrom(strings.TrimSpace(line[len("schedulefrom"):]), lineNo, &model); err != nil {
+				return Model{}, err
+			}
+			continue
 		case strings.HasPrefix(lower, "tickinterval"):
 			parseTickInterval(strings.TrimSpace(line[len("tickinterval"):]), &model)
 			continue
@@ -87,6 +99,11 @@ func Parse(src string) (Model, error) {
 				model.Calendar.Timezone = strings.TrimSpace(fields[1])
 			}
 			continue
+		case strings.HasPrefix(lower, "excludes ics "):
+			if err := parseICSDirective(line[len("excludes ics "):], lineNo, opt, &model); err != nil {
+				return Model{}, err
+			}
+			continue
 		case strings.HasPrefix(lower, "excludes"):
 			parseCalendarDates(strings.TrimSpace(line[len("excludes"):]), true, model.DateFormat, &model)
 			continue
@@ -96,9 +113,27 @@ func Parse(src string) (Model, error) {
 		case strings.HasPrefix(lower, "weekend"):
 			parseWeekendDirective(strings.TrimSpace(line[len("weekend"):]), &model)
 			continue
+		case strings.HasPrefix(lower, "holidays "):
+			if err := parseHolidaysDirective(strings.TrimSpace(line[len("holidays"):]), lineNo, &model); err != nil {
+				return Model{}, err
+			}
+			continue
+		case strings.HasPrefix(lower, "workhours "):
+			if err := parseWorkHours(strings.TrimSpace(line[len("workhours"):]), lineNo, &model); err != nil {
+				return Model{}, err
+			}
+			continue
+		case strings.HasPrefix(lower, "resource ") && !strings.Contains(line, ":"):
+			if err := parseResourceDirective(strings.TrimSpace(line[len("resource"):]), lineNo, &model); err != nil {
+				return Model{}, err
+			}
+			continue
 		case strings.HasPrefix(lower, "section"):
-			sectionName = strings.TrimSpace(line[len("section"):])
-			model.Sections = append(model.Sections, Section{Name: sectionName})
+			name, err := addSection(strings.TrimSpace(line[len("section"):]), lineNo, &model)
+			if err != nil {
+				return Model{}, err
+			}
+			sectionName = name
 			continue
 		default:
 			task, err := parseTaskLine(line, lineNo, sectionName, model.DateFormat, model.Calendar)
@@ -148,13 +183,23 @@ func parseTodayMarker(expr, layout string, model *Model) {
 		model.Today.HasDate = true
 		return
 	}
+	if isRelativeDate(expr, layout) {
+		model.Today.Expr = expr
+	}
 	model.Today.Enabled = true
 }
 
 func parseCalendarDates(expr string, exclude bool, layout string, model *Model) {
-	parts := strings.FieldsFunc(expr, func(r rune) bool {
-		return r == ' ' || r == ',' || r == '\t'
-	})
+	var parts []string
+	for _, chunk := range strings.Split(expr, ",") {
+		chunk = strings.TrimSpace(chunk)
+		// 含空格的相对表达式（如 "2025-01-06 +3d"、"end of month"）按逗号分段整体识别
+		if strings.ContainsAny(chunk, " \t") && isRelativeDate(chunk, layout) {
+			addCalendarExpr(chunk, exclude, model)
+			continue
+		}
+		parts = append(parts, strings.Fields(chunk)...)
+	}
 	for _, p := range parts {
 		if p == "" {
 			continue
@@ -183,10 +228,22 @@ func parseCalendarDates(expr string, exclude bool, layout string, model *Model)
 			} else {
 				model.Calendar.IncludeDates = append(model.Calendar.IncludeDates, t)
 			}
+			continue
+		}
+		if isRelativeDate(p, layout) {
+			addCalendarExpr(p, exclude, model)
 		}
 	}
 }
 
+func addCalendarExpr(expr string, exclude bool, model *Model) {
+	if exclude {
+		model.ExcludeExprs = append(model.ExcludeExprs, expr)
+	} else {
+		model.IncludeExprs = append(model.IncludeExprs, expr)
+	}
+}
+
 func parseWeekendDirective(expr string, model *Model) {
 	model.Calendar.ExcludeWeekend = true
 	tokens := strings.Fields(expr)
@@ -330,11 +387,15 @@ func convertStrftimeLayout(format string) string {
 
 // nolint:gocyclo // 复杂度较高，后续按 refactor-design.md 拆分
 func parseTaskLine(line string, lineNo int, section, layout string, cal Calendar) (Task, error) {
-	parseDeps := func(field string, typ DependencyType) []Dependency {
-		depStr := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(field, "after"), "before"))
-		depStr = strings.TrimSpace(strings.TrimPrefix(depStr, "until"))
+	parseDeps := func(field, keyword string, typ DependencyType) []Dependency {
+		depStr := strings.TrimSpace(field[len(keyword):])
 		var deps []Dependency
 		for _, tok := range strings.Fields(depStr) {
+			// "+2d"/"-1d" 作为前一个依赖目标的 lag/lead
+			if lag, ok := parseLag(tok); ok && len(deps) > 0 {
+				deps[len(deps)-1].Lag = lag
+				continue
+			}
 			deps = append(deps, Dependency{Type: typ, Target: tok})
 		}
 		return deps
@@ -399,24 +460,79 @@ func parseTaskLine(line string, lineNo int, section, layout string, cal Calendar
 			task.IsVertical = true
 			task.IsMilestone = false
 		case strings.HasPrefix(lower, "after"):
-			deps = append(deps, parseDeps(field, DepAfter)...)
+			deps = append(deps, parseDeps(field, "after", DepAfter)...)
 			task.StartExpr = strings.TrimSpace(field)
-		case strings.HasPrefix(lower, "before"), strings.HasPrefix(lower, "until"):
-			deps = append(deps, parseDeps(field, DepBefore)...)
+		case strings.HasPrefix(lower, "before"):
+			deps = append(deps, parseDeps(field, "before", DepBefore)...)
+		case strings.HasPrefix(lower, "until"):
+			deps = append(deps, parseDeps(field, "until", DepBefore)...)
+		case strings.HasPrefix(lower, "every "):
+			rec, err := parseRecurrence(strings.TrimSpace(field[len("every"):]), layout, cal.Timezone)
+			if err != nil {
+				return Task{}, newParseError(lineNo, 1, fmt.Sprintf("invalid recurrence %q: %v", field, err))
+			}
+			task.Recurrence = &rec
+		case constraintKeyword(lower) != "":
+			keyword := constraintKeyword(lower)
+			c, err := parseConstraint(strings.TrimSpace(field[len(keyword):]), constraintKeywords[keyword], layout, cal.Timezone)
+			if err != nil {
+				return Task{}, newParseError(lineNo, 1, fmt.Sprintf("invalid %s: %v", field[:len(keyword)], err))
+			}
+			task.Constraints = append(task.Constraints, c)
+		case dependencyKeyword(lower) != "":
+			keyword := dependencyKeyword(lower)
+			deps = append(deps, parseDeps(field, keyword, dependencyKeywords[keyword])...)
+		case strings.HasPrefix(lower, costPrefix):
+			cost, err := parseCost(field)
+			if err != nil {
+				return Task{}, newParseError(lineNo, 1, err.Error())
+			}
+			task.Cost = cost
+		case strings.HasPrefix(lower, effortPrefix):
+			effort, err := parseEffort(field)
+			if err != nil {
+				return Task{}, newParseError(lineNo, 1, err.Error())
+			}
+			task.Effort = effort
+		case assignmentRe.MatchString(field):
+			name, units, _, err := parseAssignment(field)
+			if err != nil {
+				return Task{}, newParseError(lineNo, 1, err.Error())
+			}
+			if task.Units == nil {
+				task.Units = make(map[string]int)
+			}
+			task.Resources = append(task.Resources, name)
+			task.Units[name] = units
 		case strings.Contains(field, "%"):
 			if p := parseProgress(field); p >= 0 {
 				task.Progress = p
 			}
+		case looksLikeEstimate(field):
+			est, err := parseEstimate(field)
+			if err != nil {
+				return Task{}, newParseError(lineNo, 1, err.Error())
+			}
+			task.Estimate = &est
+			task.Duration = est.Likely
+			task.DurationExplicit = true
 		case looksLikeDuration(field):
 			task.Duration = parseDurationSpec(field)
 			task.DurationExplicit = true
 		case isDate(field, layout):
 			dateCount++
-			if !task.HasStart {
+			if !task.HasStart && !isRelativeDate(task.StartExpr, layout) {
 				addDate(&task, field, true)
 			} else if !task.HasEnd {
 				addDate(&task, field, false)
 			}
+		case isRelativeDate(field, layout):
+			// 相对日期依赖渲染时钟，延迟到 ResolveSchedule 中求值
+			if !task.HasStart && task.StartExpr == "" {
+				task.StartExpr = field
+			} else if !task.HasEnd && task.EndExpr == "" {
+				task.EndExpr = field
+			}
 		default:
 			if task.ID == "" && isIdentifierCandidate(field) {
 				task.ID = field
@@ -449,14 +565,18 @@ func parseTaskLine(line string, lineNo int, section, layout string, cal Calendar
 		}
 		task.IsMilestone = false
 	}
+	if task.Effort.Value > 0 && task.DurationExplicit && !task.IsMilestone {
+		return Task{}, newParseError(lineNo, 1, fmt.Sprintf("task %s: effort and duration cannot both be set", name))
+	}
+	// 重复任务的范围由实例展开决定，不能再指定结束日期或 before/until
+	if task.Recurrence != nil && (task.HasEnd || task.EndExpr != "" || hasDependencyType(deps, DepBefore)) {
+		return Task{}, newParseError(lineNo, 1, fmt.Sprintf("task %s: recurrence cannot be combined with an end date or before/until", name))
+	}
 	// 若提供开始和结束日期，转换为持续时间
 	if task.HasStart && task.HasEnd {
-		spanDays := int(task.End.Sub(task.Start).Hours()/hoursPerDayInt) + 1 // inclusive of end date
-		if spanDays <= 0 {
-			spanDays = 1
-		}
-		task.Duration = DurationSpec{Value: spanDays, Unit: DurationDay}
+		task.Duration = DurationSpec{Value: inclusiveSpanDays(task.Start, task.End), Unit: DurationDay}
 		task.DurationExplicit = true
+		task.Estimate = nil
 	}
 
 	if task.ID == "" {
@@ -471,13 +591,32 @@ func parseTaskLine(line string, lineNo int, section, layout string, cal Calendar
 	return task, nil
 }
 
-// ParseFile 从文件读取并解析。
+// hasDependencyType 判断依赖列表中是否包含指定类型。
+func hasDependencyType(deps []Dependency, typ DependencyType) bool {
+	for _, d := range deps {
+		if d.Type == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// inclusiveSpanDays 计算起止日期（含结束日）覆盖的天数，至少 1 天。
+func inclusiveSpanDays(start, end time.Time) int {
+	spanDays := int(end.Sub(start).Hours()/hoursPerDayInt) + 1
+	if spanDays <= 0 {
+		spanDays = 1
+	}
+	return spanDays
+}
+
+// ParseFile 从文件读取并解析，excludes ics 仅允许引用源文件目录下的日历。
 func ParseFile(path string) (Model, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return Model{}, fmt.Errorf("read source file: %w", err)
 	}
-	return Parse(string(data))
+	return ParseWithOptions(string(data), ParseOptions{AllowLocalFiles: true, BaseDir: filepath.Dir(path)})
 }
 
 func dateLayout(format string) string {
@@ -633,6 +772,77 @@ func parseDurationSpec(val string) DurationSpec {
 	return DurationSpec{Value: value, Unit: unit}
 }
 
+// dependencyKeywords 列出 after/before 之外的依赖关键字：
+// with/ss 为开始-开始，finishwith/ff 为完成-完成，sf 为开始-完成。
+var dependencyKeywords = map[string]DependencyType{
+	"with":       DepStartStart,
+	"ss":         DepStartStart,
+	"finishwith": DepFinishFinish,
+	"ff":         DepFinishFinish,
+	"sf":         DepStartFinish,
+}
+
+// dependencyKeyword 返回字段使用的依赖关键字（需后跟空白），否则返回空串。
+func dependencyKeyword(lower string) string {
+	keyword, _, found := strings.Cut(lower, " ")
+	if !found {
+		return ""
+	}
+	if _, ok := dependencyKeywords[keyword]; ok {
+		return keyword
+	}
+	return ""
+}
+
+// constraintKeywords 将约束关键字（小写）映射到约束类型。
+var constraintKeywords = map[string]ConstraintType{
+	"noearlierthan": ConstraintNoEarlierThan,
+	"nolaterthan":   ConstraintNoLaterThan,
+	"muststarton":   ConstraintMustStartOn,
+	"deadline":      ConstraintDeadline,
+}
+
+// constraintKeyword 返回字段使用的约束关键字（需后跟空白），否则返回空串。
+func constraintKeyword(lower string) string {
+	keyword, _, found := strings.Cut(lower, " ")
+	if !found {
+		return ""
+	}
+	if _, ok := constraintKeywords[keyword]; ok {
+		return keyword
+	}
+	return ""
+}
+
+// parseConstraint 解析约束日期；相对日期保留表达式，待 ResolveSchedule 求值。
+func parseConstraint(expr string, typ ConstraintType, layout, tz string) (Constraint, error) {
+	c := Constraint{Type: typ, Expr: expr}
+	if t, err := parseDate(expr, layout, tz); err == nil {
+		c.Date = t
+		return c, nil
+	}
+	if isRelativeDate(expr, layout) {
+		return c, nil
+	}
+	return c, fmt.Errorf("unrecognized date %q", expr)
+}
+
+// parseLag 解析带符号的依赖延迟，如 +2d、-1d、+4h。
+func parseLag(tok string) (DurationSpec, bool) {
+	if len(tok) < minLagLen || (tok[0] != '+' && tok[0] != '-') {
+		return DurationSpec{}, false
+	}
+	body := tok[1:]
+	if !looksLikeDuration(body) {
+		return DurationSpec{}, false
+	}
+	lag := parseDurationSpec(body)
+	if tok[0] == '-' {
+		lag.Value = -lag.Value
+	}
+	return lag, true
+}
+
 func isDate(val, layout string) bool {
 	_, err := time.Parse(layoutOrDefault(layout), val)
 	return err == nil
@@ -106,3 +106,35 @@ section S
 		t.Fatalf("expected end 2024-01-06, got %s", got)
 	}
 }
+
+func TestSchedule_CalendarMonths(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+section A
+Quarter :q1, 2025-01-31, 3mo
+section B
+February :f1, 2025-02-01, 1mo
+section C
+Leap :l1, 2024-01-31, 1mo
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	// 1 月 31 日加 3 个月截断到 4 月 30 日，任务占用至前一天
+	want := map[string]string{"q1": "2025-04-29", "f1": "2025-02-28", "l1": "2024-02-28"}
+	for _, sec := range m.Sections {
+		for _, task := range sec.Tasks {
+			if got := task.End.Format("2006-01-02"); got != want[task.ID] {
+				t.Fatalf("%s expected end %s, got %s", task.ID, want[task.ID], got)
+			}
+		}
+	}
+	if got := AddMonths(time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC), 1); got.Format("2006-01-02 15:04") != "2025-02-28 09:00" {
+		t.Fatalf("AddMonths should clamp to month end, got %s", got)
+	}
+}
@@ -0,0 +1,36 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pyroflux/go-mermaid-gantt/internal/holidays"
+)
+
+// parseHolidaysDirective 解析 "holidays CN 2025 2026"：假日加入排除日期，调休上班日加入 includes。
+func parseHolidaysDirective(expr string, lineNo int, model *Model) error {
+	fields := strings.Fields(expr)
+	if len(fields) < minDirectiveParts {
+		return newParseError(lineNo, 1, "holidays needs a country and at least one year, e.g. holidays CN 2025")
+	}
+	for _, f := range fields[1:] {
+		year, err := strconv.Atoi(f)
+		if err != nil {
+			return newParseError(lineNo, 1, fmt.Sprintf("invalid holidays year %q", f))
+		}
+		list, err := holidays.Lookup(fields[0], year)
+		if err != nil {
+			return newParseError(lineNo, 1, err.Error())
+		}
+		for _, h := range list {
+			if h.Workday {
+				model.Calendar.IncludeDates = append(model.Calendar.IncludeDates, h.Date)
+			} else {
+				model.Calendar.ExcludeDates = append(model.Calendar.ExcludeDates, h.Date)
+			}
+			model.Calendar.Holidays = append(model.Calendar.Holidays, Holiday{Date: h.Date, Name: h.Name, Workday: h.Workday})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package parser
+
+import "testing"
+
+func TestParse_HolidaysCN(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+holidays CN 2025
+section A
+Build :a1, 2025-01-24, 5d
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	// 01-24 周五，01-26 周日补班，01-27 周一，01-28..02-04 春节，02-05、02-06 补足 5 天
+	if got := m.Sections[0].Tasks[0].End.Format("2006-01-02"); got != "2025-02-06" {
+		t.Fatalf("expected end 2025-02-06, got %s", got)
+	}
+	if len(m.Calendar.Holidays) == 0 || m.Calendar.Holidays[0].Name != "元旦" {
+		t.Fatalf("expected named holidays, got %+v", m.Calendar.Holidays)
+	}
+}
+
+func TestParse_HolidaysInvalid(t *testing.T) {
+	for _, line := range []string{"holidays CN", "holidays XX 2025", "holidays CN 20x5", "holidays CN 2010"} {
+		if _, err := Parse("gantt\n" + line + "\nTask :a1, 2025-03-03, 1d"); err == nil {
+			t.Fatalf("expected error for %q", line)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pyroflux/go-mermaid-gantt/internal/ical"
+)
+
+// icsWorkdaysFlag 为 excludes ics 行末的可选开关：将标记为上班日的事件视为 includes。
+const icsWorkdaysFlag = "workdays"
+
+// ParseOptions 控制 Parse 读取外部资源（如 excludes ics 引用的日历文件）的方式。
+// 源文本可能来自不可信方，缺省不访问本地文件系统。
+type ParseOptions struct {
+	FS              fs.FS  // 非 nil 时 excludes ics 的路径在该文件系统内解析
+	AllowLocalFiles bool   // FS 为 nil 时是否允许读取本地文件
+	BaseDir         string // 本地文件的基准目录：非空时仅允许其下的相对路径，空表示当前目录且不限制
+}
+
+// parseICSDirective 解析 `excludes ics <path> [workdays]`，路径含空格时用双引号包裹。
+func parseICSDirective(expr string, lineNo int, opt ParseOptions, model *Model) error {
+	expr = strings.TrimSpace(expr)
+	workdays := false
+	if fields := strings.Fields(expr); len(fields) > 1 && strings.EqualFold(fields[len(fields)-1], icsWorkdaysFlag) {
+		workdays = true
+		expr = strings.TrimSpace(expr[:strings.LastIndex(expr, fields[len(fields)-1])])
+	}
+	p := strings.Trim(expr, `"`)
+	if p == "" {
+		return newParseError(lineNo, 1, "excludes ics needs a calendar file path")
+	}
+	f, err := openCalendarFile(p, opt)
+	if err != nil {
+		return newParseError(lineNo, 1, fmt.Sprintf("open calendar %q: %v", p, err))
+	}
+	defer func() { _ = f.Close() }()
+	if err := ApplyICS(model, f, workdays); err != nil {
+		return newParseError(lineNo, 1, fmt.Sprintf("calendar %q: %v", p, err))
+	}
+	return nil
+}
+
+// openCalendarFile 打开 excludes ics 引用的日历：优先在 FS 内查找；本地文件须显式允许，
+// 指定 BaseDir 时拒绝绝对路径与逃出该目录的路径。
+func openCalendarFile(p string, opt ParseOptions) (io.ReadCloser, error) {
+	if opt.FS != nil {
+		return opt.FS.Open(path.Clean(filepath.ToSlash(p)))
+	}
+	if !opt.AllowLocalFiles {
+		return nil, errors.New("local calendar files are not allowed")
+	}
+	if opt.BaseDir == "" {
+		return os.Open(p)
+	}
+	if filepath.IsAbs(p) {
+		return nil, errors.New("absolute calendar paths are not allowed")
+	}
+	full := filepath.Join(opt.BaseDir, p)
+	rel, err := filepath.Rel(opt.BaseDir, full)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, errors.New("calendar path escapes the source directory")
+	}
+	return os.Open(full)
+}
+
+// ApplyICS 读取 RFC 5545 日历，将全天与跨天事件（含年度 RRULE）加入排除日期；
+// workdays 为 true 时，标记为上班日的事件（如调休补班）改为加入 includes。
+func ApplyICS(m *Model, r io.Reader, workdays bool) error {
+	cal, err := ical.Parse(r)
+	if err != nil {
+		return err
+	}
+	for _, ev := range cal.Events {
+		include := workdays && ev.IsWorkday()
+		for _, d := range ev.Dates() {
+			if ev.Summary != "" {
+				m.Calendar.Holidays = append(m.Calendar.Holidays, Holiday{Date: d, Name: ev.Summary, Workday: include})
+			}
+			if include {
+				m.Calendar.IncludeDates = append(m.Calendar.IncludeDates, d)
+			} else {
+				m.Calendar.ExcludeDates = append(m.Calendar.ExcludeDates, d)
+			}
+		}
+	}
+	return nil
+}
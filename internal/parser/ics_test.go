@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParse_ExcludesICS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"cal/cn.ics": {Data: []byte(`BEGIN:VCALENDAR
+BEGIN:VEVENT
+SUMMARY:Spring Festival
+DTSTART;VALUE=DATE:20250128
+DTEND;VALUE=DATE:20250205
+END:VEVENT
+BEGIN:VEVENT
+SUMMARY:Workday
+CATEGORIES:WORKDAY
+DTSTART;VALUE=DATE:20250208
+END:VEVENT
+END:VCALENDAR
+`)},
+	}
+	src := `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+excludes ics cal/cn.ics workdays
+section A
+Build :a1, 2025-01-27, 3d
+`
+	m, err := ParseWithOptions(src, ParseOptions{FS: fsys})
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	// 01-27 工作，01-28..02-04 假期，02-05、02-06 工作
+	if got := m.Sections[0].Tasks[0].End.Format("2006-01-02"); got != "2025-02-06" {
+		t.Fatalf("expected end 2025-02-06, got %s", got)
+	}
+
+	// 补班日（周六）仅在 workdays 开关下视为工作日
+	src = `gantt
+excludes weekends
+excludes ics cal/cn.ics workdays
+section A
+Build :a1, 2025-02-07, 2d
+`
+	if m, err = ParseWithOptions(src, ParseOptions{FS: fsys}); err == nil {
+		m, err = ResolveSchedule(m)
+	}
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if got := m.Sections[0].Tasks[0].End.Format("2006-01-02"); got != "2025-02-08" {
+		t.Fatalf("expected workday 2025-02-08 to count, got end %s", got)
+	}
+}
+
+func TestParse_ExcludesICSMissing(t *testing.T) {
+	_, err := ParseWithOptions("gantt\nexcludes ics nope.ics\nTask :a1, 2025-01-01, 1d\n", ParseOptions{FS: fstest.MapFS{}})
+	pe, ok := err.(ParseError)
+	if !ok || pe.Line != 2 {
+		t.Fatalf("expected ParseError on line 2, got %v", err)
+	}
+}
+
+func TestParse_ExcludesICSLocalAccess(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "cal.ics"), []byte("BEGIN:VCALENDAR\nEND:VCALENDAR\n"), 0o600); err != nil {
+		t.Fatalf("write calendar: %v", err)
+	}
+	src := func(p string) string { return "gantt\nexcludes ics " + p + "\nTask :a1, 2025-01-01, 1d\n" }
+
+	// 未显式允许时不读取本地文件
+	if _, err := ParseWithOptions(src("/etc/passwd"), ParseOptions{}); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Fatalf("expected local access to be refused, got %v", err)
+	}
+	local := ParseOptions{AllowLocalFiles: true, BaseDir: dir}
+	if _, err := ParseWithOptions(src("cal.ics"), local); err != nil {
+		t.Fatalf("expected calendar under base dir to load: %v", err)
+	}
+	for _, p := range []string{"/etc/passwd", "../../etc/passwd", "sub/../../cal.ics"} {
+		_, err := ParseWithOptions(src(p), local)
+		if err == nil || strings.Contains(err.Error(), "no such file") ||
+			!(strings.Contains(err.Error(), "absolute") || strings.Contains(err.Error(), "escapes")) {
+			t.Fatalf("path %q: expected rejection, got %v", p, err)
+		}
+	}
+}
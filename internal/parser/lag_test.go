@@ -0,0 +1,37 @@
+package parser
+
+import "testing"
+
+func TestSchedule_DependencyLag(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+section S
+Design :d1, 2025-01-06, 5d
+Review wait :r1, after d1 +3d, 1d
+section T
+Overlap :o1, after d1 -1d, 2d
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	deps := m.Sections[0].Tasks[1].Dependencies
+	if len(deps) != 1 || deps[0].Target != "d1" || deps[0].Lag != (DurationSpec{Value: 3, Unit: DurationDay}) {
+		t.Fatalf("unexpected dependency: %+v", deps)
+	}
+	if got := deps[0].String(); got != "after d1 +3d" {
+		t.Fatalf("unexpected dependency string %q", got)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	// d1 ends Fri 2025-01-10; three working days (Mon-Wed) of lag skip the weekend
+	if got := m.Sections[0].Tasks[1].Start.Format("2006-01-02"); got != "2025-01-16" {
+		t.Fatalf("lag start expected 2025-01-16, got %s", got)
+	}
+	if got := m.Sections[1].Tasks[0].Start.Format("2006-01-02"); got != "2025-01-10" {
+		t.Fatalf("lead start expected 2025-01-10, got %s", got)
+	}
+}
@@ -0,0 +1,243 @@
+package parser
+
+import (
+	"maps"
+	"sort"
+	"time"
+)
+
+// maxLevelingIterations 限制资源平衡的迭代次数，每次迭代推迟一个任务并重新排程。
+const maxLevelingIterations = 500
+
+// LevelingMove 描述资源平衡后开始时间发生变化的任务。
+type LevelingMove struct {
+	TaskID      string
+	From        time.Time // 平衡前开始
+	To          time.Time // 平衡后开始
+	Days        int       // 推迟的工作日数
+	Direct      bool      // true 表示被平衡直接推迟，false 表示因依赖随前驱顺延
+	WithinSlack bool      // 推迟未超过平衡前的总时差，不影响项目完成
+}
+
+// LevelingReport 汇总资源平衡结果。
+type LevelingReport struct {
+	Moves      []LevelingMove
+	Unresolved []Warning // 平衡后仍存在的过载（如冲突任务均为固定日期）
+	Iterations int
+}
+
+// LevelResources 对未排程的模型执行资源平衡：反复取最早的过载，推迟其中一个可移动任务
+// （优先在时差内可消解的非关键任务，其次时差最大者），再完整重新排程，直至无过载。
+// 依赖、excludes 与约束均由 ResolveSchedule 保证；m 本身不会被修改。
+func LevelResources(m Model) (Model, LevelingReport, error) {
+	base, err := ResolveSchedule(cloneModel(m))
+	if err != nil {
+		return Model{}, LevelingReport{}, err
+	}
+	var report LevelingReport
+	delays := make(map[taskPos]time.Time)
+	skipped := make(map[string]bool)
+	cur := base
+	for report.Iterations < maxLevelingIterations {
+		w, ok := firstOverAllocation(cur, skipped)
+		if !ok {
+			break
+		}
+		pos, start, ok := pickLevelingDelay(cur, w)
+		if !ok {
+			skipped[overAllocationKey(w)] = true
+			continue
+		}
+		report.Iterations++
+		delays[pos] = start
+		next := cloneModel(m)
+		for p, d := range delays {
+			next.Sections[p.section].Tasks[p.task].LevelStart = d
+		}
+		if cur, err = ResolveSchedule(next); err != nil {
+			return Model{}, LevelingReport{}, err
+		}
+	}
+
+	for _, w := range cur.Warnings {
+		if w.Kind == WarningOverAllocated {
+			report.Unresolved = append(report.Unresolved, w)
+		}
+	}
+	before := make(map[string]Task)
+	for _, sec := range base.Sections {
+		for _, t := range sec.Tasks {
+			before[t.ID] = t
+		}
+	}
+	for si, sec := range cur.Sections {
+		for ti, t := range sec.Tasks {
+			orig, ok := before[t.ID]
+			if !ok || t.IsVertical || t.Start.Equal(orig.Start) {
+				continue
+			}
+			_, direct := delays[taskPos{si, ti}]
+			days := cur.Calendar.WorkingDaysBetween(orig.Start, t.Start)
+			report.Moves = append(report.Moves, LevelingMove{
+				TaskID:      t.ID,
+				From:        orig.Start,
+				To:          t.Start,
+				Days:        days,
+				Direct:      direct,
+				WithinSlack: days <= orig.TotalSlack,
+			})
+		}
+	}
+	return cur, report, nil
+}
+
+type taskPos struct {
+	section, task int
+}
+
+func firstOverAllocation(m Model, skipped map[string]bool) (Warning, bool) {
+	var found Warning
+	ok := false
+	for _, w := range m.Warnings {
+		if w.Kind != WarningOverAllocated || skipped[overAllocationKey(w)] {
+			continue
+		}
+		if !ok || w.Date.Before(found.Date) {
+			found = w
+			ok = true
+		}
+	}
+	return found, ok
+}
+
+func overAllocationKey(w Warning) string {
+	key := w.Resource + "@" + formatDay(w.Date)
+	for _, id := range w.TaskIDs {
+		key += "," + id
+	}
+	return key
+}
+
+// pickLevelingDelay 在冲突任务中选择要推迟的任务及其新的最早开始（其余冲突任务中最早结束之后）。
+func pickLevelingDelay(m Model, w Warning) (taskPos, time.Time, bool) {
+	type candidate struct {
+		pos   taskPos
+		task  *Task
+		start time.Time
+		need  int
+	}
+	positions := make(map[string]taskPos)
+	for si := range m.Sections {
+		for ti := range m.Sections[si].Tasks {
+			positions[m.Sections[si].Tasks[ti].ID] = taskPos{si, ti}
+		}
+	}
+	taskAt := func(p taskPos) *Task { return &m.Sections[p.section].Tasks[p.task] }
+
+	var cands []candidate
+	for _, id := range w.TaskIDs {
+		pos, ok := positions[id]
+		if !ok || !levelable(taskAt(pos)) {
+			continue
+		}
+		t := taskAt(pos)
+		var release time.Time
+		for _, other := range w.TaskIDs {
+			if other == id {
+				continue
+			}
+			if op, ok := positions[other]; ok {
+				if finish := taskFinish(taskAt(op)); release.IsZero() || finish.Before(release) {
+					release = finish
+				}
+			}
+		}
+		if release.IsZero() || !release.After(t.Start) {
+			release = startOfNextDay(w.Date)
+		}
+		if release.Equal(dayStart(release, release.Location())) {
+			for i := 0; i < maxCalendarIterations && !m.Calendar.IsWorkingDay(release); i++ {
+				release = release.AddDate(0, 0, 1)
+			}
+		}
+		cands = append(cands, candidate{pos: pos, task: t, start: release, need: m.Calendar.WorkingDaysBetween(t.Start, release)})
+	}
+	if len(cands) == 0 {
+		return taskPos{}, time.Time{}, false
+	}
+	sort.SliceStable(cands, func(i, j int) bool {
+		a, b := cands[i], cands[j]
+		aFits, bFits := a.task.TotalSlack >= a.need, b.task.TotalSlack >= b.need
+		if aFits != bFits {
+			return aFits
+		}
+		if a.task.Critical != b.task.Critical {
+			return !a.task.Critical
+		}
+		if a.task.TotalSlack != b.task.TotalSlack {
+			return a.task.TotalSlack > b.task.TotalSlack
+		}
+		return a.task.Start.After(b.task.Start)
+	})
+	return cands[0].pos, cands[0].start, true
+}
+
+// levelable 判断任务能否被资源平衡推迟：固定起止、mustStartOn 与重复任务保持不动。
+func levelable(t *Task) bool {
+	if t.IsVertical || t.Recurrence != nil || (t.HasStart && t.HasEnd) {
+		return false
+	}
+	for _, c := range t.Constraints {
+		if c.Type == ConstraintMustStartOn {
+			return false
+		}
+	}
+	return true
+}
+
+// taskFinish 返回任务占用结束的时刻（不含）：日级任务为结束日的次日零点。
+func taskFinish(t *Task) time.Time {
+	if t.HasTime || t.Duration.Unit == DurationMinute || t.Duration.Unit == DurationHour {
+		return t.End
+	}
+	return startOfNextDay(t.End)
+}
+
+// cloneModel 深拷贝模型中的可变部分，便于在同一份源上多次排程。
+func cloneModel(m Model) Model {
+	out := m
+	out.Sections = make([]Section, len(m.Sections))
+	for si, sec := range m.Sections {
+		out.Sections[si] = Section{Name: sec.Name, Path: sec.Path, Tasks: make([]Task, len(sec.Tasks))}
+		for ti, t := range sec.Tasks {
+			out.Sections[si].Tasks[ti] = cloneTask(t)
+		}
+	}
+	out.Verticals = make([]Task, len(m.Verticals))
+	for vi, v := range m.Verticals {
+		out.Verticals[vi] = cloneTask(v)
+	}
+	out.Resources = append([]Resource(nil), m.Resources...)
+	out.Warnings = append([]Warning(nil), m.Warnings...)
+	out.Loads = append([]ResourceLoad(nil), m.Loads...)
+	out.ExcludeExprs = append([]string(nil), m.ExcludeExprs...)
+	out.IncludeExprs = append([]string(nil), m.IncludeExprs...)
+	out.Calendar.WeekendDays = append([]time.Weekday(nil), m.Calendar.WeekendDays...)
+	out.Calendar.ExcludeDates = append([]time.Time(nil), m.Calendar.ExcludeDates...)
+	out.Calendar.IncludeDates = append([]time.Time(nil), m.Calendar.IncludeDates...)
+	out.Calendar.Holidays = append([]Holiday(nil), m.Calendar.Holidays...)
+	return out
+}
+
+func cloneTask(t Task) Task {
+	t.Resources = append([]string(nil), t.Resources...)
+	t.Units = maps.Clone(t.Units)
+	t.Dependencies = append([]Dependency(nil), t.Dependencies...)
+	t.Constraints = append([]Constraint(nil), t.Constraints...)
+	t.Occurrences = append([]Occurrence(nil), t.Occurrences...)
+	if t.Recurrence != nil {
+		rec := *t.Recurrence
+		t.Recurrence = &rec
+	}
+	return t
+}
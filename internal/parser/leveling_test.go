@@ -0,0 +1,51 @@
+package parser
+
+import "testing"
+
+func TestLevelResources(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+section API
+Build :a1, 2025-03-03, 3d, alice
+Ship :s1, after a1, 2d
+section Web
+Screens :w1, 2025-03-03, 2d, alice
+section Docs
+Guide :g1, 2025-03-03, 2d, alice
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	leveled, report, err := LevelResources(m)
+	if err != nil {
+		t.Fatalf("leveling failed: %v", err)
+	}
+	if len(report.Unresolved) != 0 {
+		t.Fatalf("expected all conflicts resolved, got %v", report.Unresolved)
+	}
+	starts := make(map[string]string)
+	for _, sec := range leveled.Sections {
+		for _, task := range sec.Tasks {
+			starts[task.ID] = formatDay(task.Start)
+		}
+	}
+	// a1 在关键路径上保持不动；g1 在时差内推迟，w1 超出时差排到下周
+	want := map[string]string{"a1": "2025-03-03", "s1": "2025-03-06", "g1": "2025-03-06", "w1": "2025-03-10"}
+	for id, day := range want {
+		if starts[id] != day {
+			t.Fatalf("task %s expected start %s, got %s (report %+v)", id, day, starts[id], report.Moves)
+		}
+	}
+	if len(report.Moves) != 2 || !report.Moves[0].Direct || report.Moves[0].Days != 5 || report.Moves[1].Days != 3 {
+		t.Fatalf("unexpected moves: %+v", report.Moves)
+	}
+	if report.Moves[0].WithinSlack || !report.Moves[1].WithinSlack {
+		t.Fatalf("expected w1 beyond its slack and g1 within it: %+v", report.Moves)
+	}
+	// 原模型未被修改
+	if !m.Sections[1].Tasks[0].Start.Equal(m.Sections[2].Tasks[0].Start) {
+		t.Fatalf("input model was mutated")
+	}
+}
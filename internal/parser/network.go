@@ -0,0 +1,291 @@
+package parser
+
+import "time"
+
+// netNode 为关键路径分析中的任务节点，时间以工作日索引表示：
+// es 为开始前的工作日数，ef 为结束日（含）为止的工作日数，二者之差即占用的工作日。
+type netNode struct {
+	task   *Task
+	es, ef int
+	ls, lf int
+	pinned bool // 倒排时位置固定（显式日期等），最迟时间等于最早时间
+}
+
+// netEdge 描述节点间的时序约束，lag 以工作日计。
+type netEdge struct {
+	from, to int
+	typ      DependencyType
+	lag      int
+	dep      *Dependency // 显式依赖；同 section 顺接时为 nil
+}
+
+type network struct {
+	nodes []netNode
+	edges []netEdge
+	out   [][]int // 节点出边在 edges 中的下标
+	order []int   // 拓扑序
+	end   int     // 项目完成的工作日索引
+	base  time.Time
+}
+
+// analyzeNetwork 以排程结果作为前推（最早时间），沿依赖图反推最迟时间与时差，
+// 标记总时差不大于 0 的任务及其驱动依赖为关键路径。
+func analyzeNetwork(m *Model, loc *time.Location) {
+	net := buildNetwork(m, loc)
+	if net == nil {
+		return
+	}
+	for i := range net.nodes {
+		n := &net.nodes[i]
+		t := n.task
+		t.TotalSlack = n.ls - n.es
+		t.Critical = t.TotalSlack <= 0
+		free := net.end - n.ef
+		for _, ei := range net.out[i] {
+			e := net.edges[ei]
+			if gap := edgeGap(e, *n, net.nodes[e.to]); gap < free {
+				free = gap
+			}
+		}
+		if free > t.TotalSlack {
+			free = t.TotalSlack
+		}
+		if free < 0 {
+			free = 0
+		}
+		t.FreeSlack = free
+		t.LateStart = atClock(workingDayAt(net.base, n.ls, m.Calendar), t.Start.In(loc))
+		if n.lf > n.ls && t.End.After(t.Start) {
+			t.LateFinish = atClock(workingDayAt(net.base, n.lf-1, m.Calendar), t.End.In(loc))
+		} else {
+			t.LateFinish = t.LateStart
+		}
+	}
+	for _, e := range net.edges {
+		if e.dep == nil {
+			continue
+		}
+		from, to := net.nodes[e.from], net.nodes[e.to]
+		e.dep.Critical = from.task.Critical && to.task.Critical && edgeGap(e, from, to) == 0
+	}
+}
+
+// buildNetwork 建立依赖图并完成反推；图中存在环时返回 nil。
+func buildNetwork(m *Model, loc *time.Location) *network {
+	net := &network{}
+	index := make(map[string]int)
+	var base time.Time
+	for si := range m.Sections {
+		for ti := range m.Sections[si].Tasks {
+			t := &m.Sections[si].Tasks[ti]
+			if t.IsVertical || t.Start.IsZero() {
+				continue
+			}
+			index[t.ID] = len(net.nodes)
+			net.nodes = append(net.nodes, netNode{task: t})
+			day := dayStart(t.Start, loc)
+			if base.IsZero() || day.Before(base) {
+				base = day
+			}
+		}
+	}
+	if len(net.nodes) == 0 {
+		return nil
+	}
+	net.base = base
+	for i := range net.nodes {
+		n := &net.nodes[i]
+		n.es = m.Calendar.WorkingDaysBetween(base, n.task.Start.In(loc))
+		n.ef = m.Calendar.WorkingDaysBetween(base, startOfNextDay(n.task.End.In(loc)))
+		if n.ef < n.es {
+			n.ef = n.es
+		}
+		if n.ef > net.end {
+			net.end = n.ef
+		}
+	}
+	if m.ScheduleFromEnd && !m.ProjectEnd.IsZero() {
+		// 倒排模式以项目完成日为终点计算最迟时间与时差
+		net.end = m.Calendar.WorkingDaysBetween(base, startOfNextDay(calendarDay(m.ProjectEnd, loc)))
+	}
+
+	addEdge := func(from, to string, typ DependencyType, lag DurationSpec, dep *Dependency) {
+		fi, ok1 := index[from]
+		ti, ok2 := index[to]
+		if !ok1 || !ok2 || fi == ti {
+			return
+		}
+		net.edges = append(net.edges, netEdge{from: fi, to: ti, typ: typ, lag: lagDays(lag), dep: dep})
+	}
+	for si := range m.Sections {
+		var prev *Task
+		for ti := range m.Sections[si].Tasks {
+			t := &m.Sections[si].Tasks[ti]
+			if t.IsVertical {
+				continue
+			}
+			hasBefore := false
+			for di := range t.Dependencies {
+				dep := &t.Dependencies[di]
+				switch dep.Type {
+				case DepBefore:
+					hasBefore = true
+					addEdge(t.ID, dep.Target, DepAfter, dep.Lag, dep)
+				default:
+					addEdge(dep.Target, t.ID, dep.Type, dep.Lag, dep)
+				}
+			}
+			// 未指定开始的任务在排程中顺接同 section 前一任务
+			if prev != nil && !t.HasStart && !hasBefore {
+				addEdge(prev.ID, t.ID, DepAfter, DurationSpec{}, nil)
+			}
+			prev = t
+		}
+	}
+
+	net.out = make([][]int, len(net.nodes))
+	indegree := make([]int, len(net.nodes))
+	for ei, e := range net.edges {
+		net.out[e.from] = append(net.out[e.from], ei)
+		indegree[e.to]++
+	}
+	queue := make([]int, 0, len(net.nodes))
+	for i, d := range indegree {
+		if d == 0 {
+			queue = append(queue, i)
+		}
+	}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		net.order = append(net.order, i)
+		for _, ei := range net.out[i] {
+			to := net.edges[ei].to
+			if indegree[to]--; indegree[to] == 0 {
+				queue = append(queue, to)
+			}
+		}
+	}
+	if len(net.order) != len(net.nodes) {
+		return nil
+	}
+
+	// 排程结果受粒度（同日的小时任务）或强制约束影响可能与依赖不一致，
+	// 将这类负间隔折算进 lag，保证前推结果在图上可行
+	for ei := range net.edges {
+		e := &net.edges[ei]
+		if gap := edgeGap(*e, net.nodes[e.from], net.nodes[e.to]); gap < 0 {
+			e.lag += gap
+		}
+	}
+
+	net.backward(m, loc)
+	return net
+}
+
+// backward 沿拓扑逆序计算各节点的最迟完成与最迟开始。
+func (net *network) backward(m *Model, loc *time.Location) {
+	for k := len(net.order) - 1; k >= 0; k-- {
+		n := &net.nodes[net.order[k]]
+		lf := net.end
+		if deadline, ok := n.task.Deadline(); ok {
+			if d := m.Calendar.WorkingDaysBetween(net.base, startOfNextDay(dayStart(deadline, loc))); d < lf {
+				lf = d
+			}
+		}
+		dur := n.ef - n.es
+		for _, ei := range net.out[net.order[k]] {
+			e := net.edges[ei]
+			succ := net.nodes[e.to]
+			var bound int
+			switch e.typ {
+			case DepStartStart:
+				bound = succ.ls - e.lag + dur
+			case DepFinishFinish:
+				bound = succ.lf - e.lag
+			case DepStartFinish:
+				bound = succ.lf - e.lag + dur
+			default:
+				bound = succ.ls - e.lag
+			}
+			if bound < lf {
+				lf = bound
+			}
+		}
+		if n.pinned {
+			lf = n.ef
+		}
+		n.lf = lf
+		n.ls = lf - dur
+	}
+}
+
+// edgeGap 返回后继在最早时间下距离约束边界的余量（工作日）。
+func edgeGap(e netEdge, from, to netNode) int {
+	switch e.typ {
+	case DepStartStart:
+		return to.es - (from.es + e.lag)
+	case DepFinishFinish:
+		return to.ef - (from.ef + e.lag)
+	case DepStartFinish:
+		return to.ef - (from.es + e.lag)
+	default:
+		return to.es - (from.ef + e.lag)
+	}
+}
+
+// lagDays 将依赖延迟折算为工作日；小时/分钟级延迟不足一天时按 0 计。
+func lagDays(lag DurationSpec) int {
+	if lag.Unit == DurationDay {
+		return lag.Value
+	}
+	return int(durationToDuration(lag).Hours() / hoursPerDay)
+}
+
+// workingDayAt 返回自 base 起第 idx 个工作日（从 0 计），idx 为负时向前查找。
+func workingDayAt(base time.Time, idx int, cal Calendar) time.Time {
+	limit := maxCalendarIterations
+	if idx > 0 {
+		limit += idx * daysPerWeek
+	} else {
+		limit -= idx * daysPerWeek
+	}
+	d := base
+	if idx >= 0 {
+		for i := 0; i < limit; i, d = i+1, d.AddDate(0, 0, 1) {
+			if !cal.IsWorkingDay(d) {
+				continue
+			}
+			if idx == 0 {
+				return d
+			}
+			idx--
+		}
+		return d
+	}
+	for i := 0; i < limit; i++ {
+		d = d.AddDate(0, 0, -1)
+		if !cal.IsWorkingDay(d) {
+			continue
+		}
+		if idx++; idx == 0 {
+			return d
+		}
+	}
+	return d
+}
+
+// atClock 将 day 的时刻设为 clock 的时分秒。
+func atClock(day, clock time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), clock.Nanosecond(), day.Location())
+}
+
+func dayStart(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// calendarDay 以 t 自身的年月日在 loc 中构造零点，用于源中写明的日期，避免换算时区后跨日。
+func calendarDay(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
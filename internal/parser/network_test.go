@@ -0,0 +1,55 @@
+package parser
+
+import "testing"
+
+func TestSchedule_CriticalPath(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+section Build
+Design :d1, 2025-03-03, 3d
+Backend :b1, after d1, 5d
+section Docs
+Write :w1, after d1, 2d
+Announce :a1, after w1, 1d
+section Release
+Ship :s1, after b1 w1, 1d
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	want := map[string]bool{"d1": true, "b1": true, "w1": false, "s1": true, "a1": false}
+	for _, sec := range m.Sections {
+		for _, task := range sec.Tasks {
+			if task.Critical != want[task.ID] {
+				t.Fatalf("task %s critical expected %v, got %v", task.ID, want[task.ID], task.Critical)
+			}
+		}
+	}
+	ship := m.Sections[2].Tasks[0]
+	if !ship.Dependencies[0].Critical || ship.Dependencies[1].Critical {
+		t.Fatalf("expected only b1 -> s1 to be a critical link, got %+v", ship.Dependencies)
+	}
+
+	docs := m.Sections[1].Tasks
+	if docs[0].TotalSlack != 3 || docs[0].FreeSlack != 0 {
+		t.Fatalf("w1 slack expected total 3 free 0, got total %d free %d", docs[0].TotalSlack, docs[0].FreeSlack)
+	}
+	if docs[1].TotalSlack != 3 || docs[1].FreeSlack != 3 {
+		t.Fatalf("a1 slack expected total 3 free 3, got total %d free %d", docs[1].TotalSlack, docs[1].FreeSlack)
+	}
+	if got := formatDay(docs[0].LateStart); got != "2025-03-11" {
+		t.Fatalf("w1 late start expected 2025-03-11, got %s", got)
+	}
+	if got := formatDay(docs[0].LateFinish); got != "2025-03-12" {
+		t.Fatalf("w1 late finish expected 2025-03-12, got %s", got)
+	}
+	if ship.TotalSlack != 0 || !ship.LateStart.Equal(ship.Start) {
+		t.Fatalf("s1 should have no slack, got %d (late start %s)", ship.TotalSlack, formatDay(ship.LateStart))
+	}
+}
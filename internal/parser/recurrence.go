@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxOccurrences 限制单个重复任务展开的实例数，避免 until 过远时无限展开。
+const maxOccurrences = 1000
+
+var recurrenceCountRe = regexp.MustCompile(`^x([0-9]+)$`)
+
+// parseRecurrence 解析 every 之后的规则，如 "2w until 2025-06-30"、"1w x10 skip"。
+func parseRecurrence(body, layout, tz string) (Recurrence, error) {
+	fields := strings.Fields(body)
+	if len(fields) == 0 || !looksLikeDuration(fields[0]) {
+		return Recurrence{}, fmt.Errorf("missing interval")
+	}
+	rec := Recurrence{Interval: parseDurationSpec(fields[0])}
+	if rec.Interval.Value <= 0 {
+		return Recurrence{}, fmt.Errorf("interval must be positive")
+	}
+	rest := fields[1:]
+	// 末尾的 skip/shift 决定落在排除日的实例如何处理
+	if n := len(rest); n > 0 {
+		switch strings.ToLower(rest[n-1]) {
+		case "skip":
+			rec.SkipExcluded = true
+			rest = rest[:n-1]
+		case "shift":
+			rest = rest[:n-1]
+		}
+	}
+	for i := 0; i < len(rest); i++ {
+		tok := strings.ToLower(rest[i])
+		if m := recurrenceCountRe.FindStringSubmatch(tok); m != nil {
+			n, err := strconv.Atoi(m[1])
+			if err != nil || n <= 0 {
+				return Recurrence{}, fmt.Errorf("invalid count %s", rest[i])
+			}
+			rec.Count = n
+			continue
+		}
+		if tok != "until" {
+			return Recurrence{}, fmt.Errorf("unexpected token %s", rest[i])
+		}
+		j := i + 1
+		for j < len(rest) && !recurrenceCountRe.MatchString(strings.ToLower(rest[j])) {
+			j++
+		}
+		expr := strings.Join(rest[i+1:j], " ")
+		if t, err := parseDate(expr, layout, tz); err == nil {
+			rec.Until = t
+		} else if isRelativeDate(expr, layout) {
+			rec.UntilExpr = expr
+		} else {
+			return Recurrence{}, fmt.Errorf("invalid until date %q", expr)
+		}
+		i = j - 1
+	}
+	if rec.Count == 0 && rec.Until.IsZero() && rec.UntilExpr == "" {
+		return Recurrence{}, fmt.Errorf("needs until <date> or x<count>")
+	}
+	return rec, nil
+}
+
+// expandRecurrence 以 first 为首个实例展开重复任务，并将 Start/End 更新为整个序列的范围。
+func expandRecurrence(t *Task, first time.Time, cal Calendar) {
+	rec := t.Recurrence
+	var until time.Time
+	if !rec.Until.IsZero() {
+		y, m, d := rec.Until.Date()
+		until = time.Date(y, m, d, 0, 0, 0, 0, first.Location()).AddDate(0, 0, 1)
+	}
+	t.Occurrences = nil
+	for i := 0; i < maxOccurrences; i++ {
+		if rec.Count > 0 && i >= rec.Count {
+			break
+		}
+		slot := addInterval(first, rec.Interval, i)
+		if !until.IsZero() && !slot.Before(until) {
+			break
+		}
+		start := slot
+		if !cal.IsWorkingDay(start) {
+			if rec.SkipExcluded {
+				continue
+			}
+			for !cal.IsWorkingDay(start) {
+				start = start.AddDate(0, 0, 1)
+			}
+		}
+		end, _ := scheduleEnd(start, t.Duration, cal)
+		t.Occurrences = append(t.Occurrences, Occurrence{Start: start, End: end})
+	}
+	if len(t.Occurrences) > 0 {
+		t.Start = t.Occurrences[0].Start
+		t.End = t.Occurrences[len(t.Occurrences)-1].End
+	}
+}
+
+// addInterval 返回第 n 个间隔后的时间点；天/周/月按日历推算，避免累积漂移，月末日期按月底截断。
+func addInterval(first time.Time, interval DurationSpec, n int) time.Time {
+	switch interval.Unit {
+	case DurationDay:
+		return first.AddDate(0, 0, interval.Value*n)
+	case DurationWeek:
+		return first.AddDate(0, 0, interval.Value*daysPerWeek*n)
+	case DurationMonth:
+		return AddMonths(first, interval.Value*n)
+	default:
+		return first.Add(durationToDuration(interval) * time.Duration(n))
+	}
+}
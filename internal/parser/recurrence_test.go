@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSchedule_RecurringTasks(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+excludes weekends 2025-01-20
+section Rituals
+Release sync :sync, 2025-01-06, 1d, every 1w x4
+Sprint review :rev, 2025-01-06, 1d, every 2w until 2025-02-03 skip
+section Follow-up
+After series :a1, after sync, 1d
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	sync := m.Sections[0].Tasks[0]
+	var got []string
+	for _, occ := range sync.Occurrences {
+		got = append(got, formatDay(occ.Start))
+	}
+	// 2025-01-20 is excluded and shifted to the next working day by default
+	want := []string{"2025-01-06", "2025-01-13", "2025-01-21", "2025-01-27"}
+	if len(got) != len(want) {
+		t.Fatalf("expected occurrences %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected occurrences %v, got %v", want, got)
+		}
+	}
+	if formatDay(sync.Start) != "2025-01-06" || formatDay(sync.End) != "2025-01-27" {
+		t.Fatalf("series span mismatch: %s -> %s", formatDay(sync.Start), formatDay(sync.End))
+	}
+
+	rev := m.Sections[0].Tasks[1]
+	if len(rev.Occurrences) != 2 {
+		t.Fatalf("expected skip to drop the excluded review, got %d occurrences", len(rev.Occurrences))
+	}
+	if got := formatDay(m.Sections[1].Tasks[0].Start); got != "2025-01-28" {
+		t.Fatalf("successor of series expected 2025-01-28, got %s", got)
+	}
+}
+
+func TestParse_InvalidRecurrence(t *testing.T) {
+	if _, err := Parse("gantt\nSync :s1, 2025-01-06, 1d, every 1w\n"); err == nil {
+		t.Fatalf("expected error for unbounded recurrence")
+	}
+	for _, line := range []string{
+		"Sync :s1, 2025-01-06, 2025-01-07, every 1w x10",
+		"Sync :s1, 2025-01-06, until b1, every 1w x10",
+		"Sync :s1, 2025-01-06, before b1, every 1w x10",
+	} {
+		src := "gantt\nsection A\nShip :b1, 2025-03-03, 1d\n" + line + "\n"
+		if _, err := Parse(src); err == nil || !strings.Contains(err.Error(), "recurrence cannot be combined") {
+			t.Fatalf("expected recurrence/end conflict for %q, got %v", line, err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"math"
+	"time"
+)
+
+// reforecastTask 在 Reforecast 模式下处理落后于计划的任务：截至状态日（不含）计划完成比例高于实际进度时，
+// 剩余工作量 = 工期 × (1 − 进度)（按原单位向上取整）从状态日起重新排程；尚未开始的任务整体移到状态日。
+// fixed 表示起止由日期或 before/until 固定：保留开始，剩余工作量按实际占用的工作日（时间型按小时）计算。
+// 返回新的起止与跨越天数，并在任务上记录原计划起止。
+func reforecastTask(t *Task, start, end time.Time, days int, status time.Time, cal Calendar, fixed bool) (time.Time, time.Time, int) {
+	t.Reforecast = false
+	t.PlannedStart, t.PlannedEnd = time.Time{}, time.Time{}
+	if t.Status == StatusDone || t.Progress >= maxProgressPercent || t.IsMilestone || t.Duration.Value <= 0 ||
+		t.Recurrence != nil || !start.Before(status) {
+		return start, end, days
+	}
+	planned := *t
+	planned.Start, planned.End = start, end
+	done := float64(t.Progress) / maxProgressPercent
+	if plannedFraction(&planned, status, cal, cal.Location()) <= done {
+		return start, end, days
+	}
+
+	remaining := DurationSpec{Value: int(math.Ceil(float64(t.Duration.Value) * (1 - done))), Unit: t.Duration.Unit}
+	if fixed {
+		remaining = DurationSpec{Value: int(math.Ceil(taskWorkDays(&planned, cal, cal.Location()) * (1 - done))), Unit: DurationDay}
+		if t.HasTime || t.Duration.Unit == DurationHour || t.Duration.Unit == DurationMinute {
+			remaining = DurationSpec{Value: int(math.Ceil(end.Sub(start).Hours() * (1 - done))), Unit: DurationHour}
+		}
+	}
+	resume := cal.NextWorkingDay(status)
+	if usesWorkHours(remaining, cal) {
+		resume = cal.NextWorkingTime(status)
+	}
+	newEnd, _ := scheduleEnd(resume, remaining, cal)
+	newStart := start
+	if t.Progress == 0 && !fixed {
+		newStart = resume
+	}
+	t.PlannedStart, t.PlannedEnd = start, end
+	t.Reforecast = true
+	return newStart, newEnd, inclusiveSpanDays(newStart, newEnd)
+}
@@ -0,0 +1,92 @@
+package parser
+
+import "testing"
+
+func TestSchedule_Reforecast(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+todayMarker 2025-03-12
+section A
+Design :d1, 2025-03-03, 5d, done
+Build  :b1, after d1, 5d, active, 40%
+Test   :t1, after b1, 2d
+Late   :l1, 2025-03-10, 2d
+Ahead  :a1, 2025-03-10, 4d, 90%
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m.Reforecast = true
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	tasks := m.Sections[0].Tasks
+	// b1 计划 03-10..03-14，状态日前应完成 2/5 = 40%，未落后
+	if b1 := tasks[1]; b1.Reforecast || b1.End.Format("2006-01-02") != "2025-03-14" {
+		t.Fatalf("b1 is on plan and should not move: %+v", b1)
+	}
+	// l1 未开始且应已完成：整体移到状态日
+	l1 := tasks[3]
+	if !l1.Reforecast || l1.Start.Format("2006-01-02") != "2025-03-12" || l1.End.Format("2006-01-02") != "2025-03-13" ||
+		l1.PlannedEnd.Format("2006-01-02") != "2025-03-11" {
+		t.Fatalf("unexpected l1 reforecast: start %s end %s planned %s", l1.Start, l1.End, l1.PlannedEnd)
+	}
+	if tasks[4].Reforecast {
+		t.Fatalf("a1 is ahead of plan and should not move")
+	}
+
+	// 状态日推后到 03-13：b1 应完成 60%，剩余 3d 从 03-13 起，开始保持不变，后继顺延
+	m2, _ := Parse(src)
+	m2.Today.Date = m2.Today.Date.AddDate(0, 0, 1)
+	m2.Reforecast = true
+	m2, err = ResolveSchedule(m2)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	b1, t1 := m2.Sections[0].Tasks[1], m2.Sections[0].Tasks[2]
+	if !b1.Reforecast || !b1.Start.Equal(b1.PlannedStart) || b1.End.Format("2006-01-02") != "2025-03-17" {
+		t.Fatalf("unexpected b1 reforecast: %s..%s", b1.Start, b1.End)
+	}
+	if t1.Start.Format("2006-01-02") != "2025-03-18" {
+		t.Fatalf("expected successor to follow the forecast end, got %s", t1.Start)
+	}
+}
+
+func TestSchedule_ReforecastFixedDates(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+todayMarker 2025-03-17
+section A
+Build :b1, 2025-03-03, 2025-03-14, active, 30%
+Ship  :milestone, m1, after b1, 0d
+Prep  :p1, 2025-03-05, until rel
+Rel   :rel, 2025-03-20, 1d
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m.Reforecast = true
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	tasks := m.Sections[0].Tasks
+	// 固定起止：保留开始，剩余 10 × 70% = 7 个工作日自 03-17 起
+	b1 := tasks[0]
+	if !b1.Reforecast || b1.Start.Format("2006-01-02") != "2025-03-03" || b1.End.Format("2006-01-02") != "2025-03-25" ||
+		b1.PlannedEnd.Format("2006-01-02") != "2025-03-14" {
+		t.Fatalf("unexpected fixed-date reforecast: start %s end %s planned %s", b1.Start, b1.End, b1.PlannedEnd)
+	}
+	if m1 := tasks[1]; m1.Start.Format("2006-01-02") != "2025-03-26" {
+		t.Fatalf("milestone should follow the forecast end, got %s", m1.Start)
+	}
+	// until 固定结束于 03-19，未开始：剩余全部工作自状态日起
+	if p1 := tasks[2]; !p1.Reforecast || p1.Start.Format("2006-01-02") != "2025-03-05" || !p1.End.After(p1.PlannedEnd) {
+		t.Fatalf("unexpected until reforecast: %+v", p1)
+	}
+}
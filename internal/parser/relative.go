@@ -0,0 +1,298 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 相对日期表达式：<base> [(+|-)N<unit> ...]
+// base 支持 today/tomorrow/yesterday、星期名（取当日或之后最近的一天）、
+// start|end of week|month|year 以及 dateFormat 格式的绝对日期；
+// unit 与持续时间一致：m(分钟)/h/d/w/mo，另支持 y(年)。
+var (
+	relativeTailRe   = regexp.MustCompile(`(?:\s*[+-]\s*[0-9]+\s*(?:mo|[mhdwy]))+$`)
+	relativeOffsetRe = regexp.MustCompile(`([+-])\s*([0-9]+)\s*(mo|[mhdwy])`)
+)
+
+const (
+	relativeOffsetParts     = 4
+	periodBoundaryPartCount = 3 // start|end of <period>
+)
+
+type relativeOffset struct {
+	value int
+	unit  string
+}
+
+type relativeExpr struct {
+	base    string
+	offsets []relativeOffset
+}
+
+// splitRelative 将表达式拆分为基准与偏移；不是相对表达式时返回 false。
+// 纯绝对日期（无偏移）不视为相对表达式，仍由 isDate 处理。
+func splitRelative(expr, layout string) (relativeExpr, bool) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return relativeExpr{}, false
+	}
+	base := expr
+	var offsets []relativeOffset
+	if loc := relativeTailRe.FindStringIndex(expr); loc != nil {
+		base = strings.TrimSpace(expr[:loc[0]])
+		for _, m := range relativeOffsetRe.FindAllStringSubmatch(expr[loc[0]:], -1) {
+			if len(m) != relativeOffsetParts {
+				continue
+			}
+			v, err := strconv.Atoi(m[2])
+			if err != nil {
+				return relativeExpr{}, false
+			}
+			if m[1] == "-" {
+				v = -v
+			}
+			offsets = append(offsets, relativeOffset{value: v, unit: m[3]})
+		}
+	}
+	if base == "" {
+		return relativeExpr{}, false
+	}
+	if isRelativeKeyword(strings.ToLower(base)) {
+		return relativeExpr{base: base, offsets: offsets}, true
+	}
+	if len(offsets) > 0 && isDate(base, layout) {
+		return relativeExpr{base: base, offsets: offsets}, true
+	}
+	return relativeExpr{}, false
+}
+
+// isRelativeDate 判断字段是否为相对日期表达式。
+func isRelativeDate(expr, layout string) bool {
+	_, ok := splitRelative(expr, layout)
+	return ok
+}
+
+func isRelativeKeyword(base string) bool {
+	switch base {
+	case "today", "now", "tomorrow", "yesterday":
+		return true
+	}
+	if _, ok := weekdayFromString(base); ok {
+		return true
+	}
+	_, _, ok := parsePeriodBoundary(base)
+	return ok
+}
+
+// parsePeriodBoundary 解析 "start of month"、"end of week" 等边界关键字。
+func parsePeriodBoundary(base string) (end bool, period string, ok bool) {
+	fields := strings.Fields(base)
+	if len(fields) != periodBoundaryPartCount || fields[1] != "of" {
+		return false, "", false
+	}
+	switch fields[0] {
+	case "start", "beginning":
+	case "end":
+		end = true
+	default:
+		return false, "", false
+	}
+	switch fields[2] {
+	case "week", "month", "year":
+		return end, fields[2], true
+	default:
+		return false, "", false
+	}
+}
+
+// evalRelativeDate 以 ref（渲染时钟）为参照计算相对日期表达式。
+func evalRelativeDate(expr, layout string, ref time.Time, weekStart *time.Weekday) (time.Time, error) {
+	rel, ok := splitRelative(expr, layout)
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid relative date: %s", expr)
+	}
+	loc := ref.Location()
+	today := time.Date(ref.Year(), ref.Month(), ref.Day(), 0, 0, 0, 0, loc)
+
+	var t time.Time
+	lower := strings.ToLower(rel.base)
+	switch lower {
+	case "today":
+		t = today
+	case "now":
+		t = ref
+	case "tomorrow":
+		t = today.AddDate(0, 0, 1)
+	case "yesterday":
+		t = today.AddDate(0, 0, -1)
+	default:
+		if wd, ok := weekdayFromString(lower); ok {
+			t = today.AddDate(0, 0, (int(wd)-int(today.Weekday())+daysPerWeek)%daysPerWeek)
+		} else if end, period, ok := parsePeriodBoundary(lower); ok {
+			t = periodBoundary(today, period, end, weekStart)
+		} else {
+			parsed, err := parseDate(rel.base, layout, loc.String())
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid relative date base: %s", rel.base)
+			}
+			t = parsed
+		}
+	}
+
+	for _, off := range rel.offsets {
+		switch off.unit {
+		case "m":
+			t = t.Add(time.Duration(off.value) * time.Minute)
+		case "h":
+			t = t.Add(time.Duration(off.value) * time.Hour)
+		case "w":
+			t = t.AddDate(0, 0, off.value*daysPerWeek)
+		case "mo":
+			t = t.AddDate(0, off.value, 0)
+		case "y":
+			t = t.AddDate(off.value, 0, 0)
+		default:
+			t = t.AddDate(0, 0, off.value)
+		}
+	}
+	return t, nil
+}
+
+func periodBoundary(day time.Time, period string, end bool, weekStart *time.Weekday) time.Time {
+	switch period {
+	case "week":
+		ws := time.Monday
+		if weekStart != nil {
+			ws = *weekStart
+		}
+		start := day.AddDate(0, 0, -((int(day.Weekday()) - int(ws) + daysPerWeek) % daysPerWeek))
+		if end {
+			return start.AddDate(0, 0, daysPerWeek-1)
+		}
+		return start
+	case "year":
+		start := time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, day.Location())
+		if end {
+			return start.AddDate(1, 0, -1)
+		}
+		return start
+	default:
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+		if end {
+			return start.AddDate(0, 1, -1)
+		}
+		return start
+	}
+}
+
+// renderClock 返回相对表达式使用的参照时刻：Model.Clock 或当前时间。
+func renderClock(m Model, loc *time.Location) time.Time {
+	if !m.Clock.IsZero() {
+		return m.Clock.In(loc)
+	}
+	return time.Now().In(loc)
+}
+
+// resolveRelativeDates 在排程前展开任务起止、今日标记、项目完成日与 excludes/includes 中的相对表达式。
+func resolveRelativeDates(m *Model, loc *time.Location) error {
+	ref := renderClock(*m, loc)
+	eval := func(expr string) (time.Time, error) {
+		return evalRelativeDate(expr, m.DateFormat, ref, m.WeekStart)
+	}
+
+	if m.Today.Expr != "" {
+		t, err := eval(m.Today.Expr)
+		if err != nil {
+			return err
+		}
+		m.Today.Date = t
+		m.Today.HasDate = true
+	}
+	for _, expr := range m.ExcludeExprs {
+		t, err := eval(expr)
+		if err != nil {
+			return err
+		}
+		m.Calendar.ExcludeDates = append(m.Calendar.ExcludeDates, t)
+	}
+	for _, expr := range m.IncludeExprs {
+		t, err := eval(expr)
+		if err != nil {
+			return err
+		}
+		m.Calendar.IncludeDates = append(m.Calendar.IncludeDates, t)
+	}
+	m.ExcludeExprs = nil
+	m.IncludeExprs = nil
+	if m.ProjectEndExpr != "" {
+		t, err := eval(m.ProjectEndExpr)
+		if err != nil {
+			return err
+		}
+		m.ProjectEnd = t
+		m.ProjectEndExpr = ""
+	}
+
+	for si := range m.Sections {
+		for ti := range m.Sections[si].Tasks {
+			if err := resolveTaskRelative(&m.Sections[si].Tasks[ti], m.DateFormat, eval); err != nil {
+				return err
+			}
+		}
+	}
+	for vi := range m.Verticals {
+		if err := resolveTaskRelative(&m.Verticals[vi], m.DateFormat, eval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resolveTaskRelative(t *Task, layout string, eval func(string) (time.Time, error)) error {
+	changed := false
+	if !t.HasStart && isRelativeDate(t.StartExpr, layout) {
+		start, err := eval(t.StartExpr)
+		if err != nil {
+			return ParseError{Line: t.Line, Column: t.Column, Message: err.Error()}
+		}
+		t.Start = start
+		t.HasStart = true
+		t.HasTime = start.Hour() != 0 || start.Minute() != 0
+		changed = true
+	}
+	if !t.HasEnd && isRelativeDate(t.EndExpr, layout) {
+		end, err := eval(t.EndExpr)
+		if err != nil {
+			return ParseError{Line: t.Line, Column: t.Column, Message: err.Error()}
+		}
+		t.End = end
+		t.HasEnd = true
+		changed = true
+	}
+	for ci := range t.Constraints {
+		c := &t.Constraints[ci]
+		if !c.Date.IsZero() {
+			continue
+		}
+		d, err := eval(c.Expr)
+		if err != nil {
+			return ParseError{Line: t.Line, Column: t.Column, Message: err.Error()}
+		}
+		c.Date = d
+	}
+	if rec := t.Recurrence; rec != nil && rec.Until.IsZero() && rec.UntilExpr != "" {
+		until, err := eval(rec.UntilExpr)
+		if err != nil {
+			return ParseError{Line: t.Line, Column: t.Column, Message: err.Error()}
+		}
+		rec.Until = until
+	}
+	if changed && t.HasStart && t.HasEnd {
+		t.Duration = DurationSpec{Value: inclusiveSpanDays(t.Start, t.End), Unit: DurationDay}
+		t.DurationExplicit = true
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedule_RelativeDates(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+todayMarker today-3d
+excludes today+1d, end of month
+section Train
+Cut :c1, today+2w, 2d
+Ship :s1, 2025-01-06 +3d, 1d
+Sync :y1, monday+1w, 1d
+Close :z1, start of month, end of month
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m.Clock = time.Date(2025, 1, 8, 15, 0, 0, 0, time.UTC) // Wednesday
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	want := map[string]string{
+		"c1": "2025-01-22",
+		"s1": "2025-01-09",
+		"y1": "2025-01-20",
+		"z1": "2025-01-01",
+	}
+	for _, task := range m.Sections[0].Tasks {
+		if got := task.Start.Format("2006-01-02"); got != want[task.ID] {
+			t.Fatalf("task %s start expected %s, got %s", task.ID, want[task.ID], got)
+		}
+	}
+	if got := m.Sections[0].Tasks[3].End.Format("2006-01-02"); got != "2025-01-31" {
+		t.Fatalf("end of month expected 2025-01-31, got %s", got)
+	}
+	if !m.Today.HasDate || m.Today.Date.Format("2006-01-02") != "2025-01-05" {
+		t.Fatalf("today marker expected 2025-01-05, got %v", m.Today.Date)
+	}
+	if len(m.Calendar.ExcludeDates) != 2 {
+		t.Fatalf("expected 2 relative excludes, got %v", m.Calendar.ExcludeDates)
+	}
+}
+
+func TestIsRelativeDate(t *testing.T) {
+	cases := map[string]bool{
+		"today":          true,
+		"today-3d":       true,
+		"monday+1w":      true,
+		"2025-01-06 +3d": true,
+		"end of month":   true,
+		"2025-01-06":     false,
+		"3d":             false,
+		"a1":             false,
+		"+3d":            false,
+	}
+	for expr, want := range cases {
+		if got := isRelativeDate(expr, defaultDateLayout); got != want {
+			t.Fatalf("isRelativeDate(%q) = %v, want %v", expr, got, want)
+		}
+	}
+}
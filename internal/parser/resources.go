@@ -0,0 +1,271 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultCapacity 为未声明容量的资源默认容量，fullAllocation 为任务对资源的默认占用（均为百分比）。
+const (
+	defaultCapacity = 100
+	fullAllocation  = 100
+)
+
+var resourceCapacityRe = regexp.MustCompile(`^([0-9]+)%$`)
+
+// parseResourceDirective 解析 "resource alice 50%"：末尾百分比为容量，缺省 100%，名称可含空格。
+func parseResourceDirective(expr string, lineNo int, model *Model) error {
+	fields := strings.Fields(expr)
+	if len(fields) == 0 {
+		return newParseError(lineNo, 1, "resource name is empty")
+	}
+	res := Resource{Capacity: defaultCapacity, Line: lineNo}
+	if m := resourceCapacityRe.FindStringSubmatch(fields[len(fields)-1]); m != nil {
+		v, err := strconv.Atoi(m[1])
+		if err != nil || v <= 0 {
+			return newParseError(lineNo, 1, fmt.Sprintf("invalid resource capacity %q", fields[len(fields)-1]))
+		}
+		res.Capacity = v
+		fields = fields[:len(fields)-1]
+	}
+	if len(fields) == 0 {
+		return newParseError(lineNo, 1, "resource name is empty")
+	}
+	res.Name = strings.Join(fields, " ")
+	// 重复声明以最后一次为准
+	for i := range model.Resources {
+		if strings.EqualFold(model.Resources[i].Name, res.Name) {
+			model.Resources[i] = res
+			return nil
+		}
+	}
+	model.Resources = append(model.Resources, res)
+	return nil
+}
+
+// assignment 为任务对单个资源的占用。
+type assignment struct {
+	resource string
+	units    int // 百分比
+}
+
+func taskAssignments(t *Task) []assignment {
+	out := make([]assignment, 0, len(t.Resources))
+	for _, r := range t.Resources {
+		out = append(out, assignment{resource: r, units: t.UnitsFor(r)})
+	}
+	return out
+}
+
+// UnitsFor 返回任务对资源的占用百分比，未标注时为 100%。
+func (t *Task) UnitsFor(resource string) int {
+	if u, ok := t.Units[resource]; ok {
+		return u
+	}
+	return fullAllocation
+}
+
+// loadSpan 为资源上的一段占用区间 [start, end)。
+type loadSpan struct {
+	start, end time.Time
+	units      int
+	task       *Task
+}
+
+// taskSpans 返回任务实际占用的时间区间；重复任务按实例展开，里程碑不占用资源。
+func taskSpans(t *Task) [][2]time.Time {
+	timeBased := t.HasTime || t.Duration.Unit == DurationMinute || t.Duration.Unit == DurationHour
+	span := func(start, end time.Time) [2]time.Time {
+		if !timeBased {
+			end = startOfNextDay(end)
+		}
+		return [2]time.Time{start, end}
+	}
+	if t.IsMilestone || t.Duration.Value == 0 || t.Start.IsZero() {
+		return nil
+	}
+	if len(t.Occurrences) > 0 {
+		out := make([][2]time.Time, 0, len(t.Occurrences))
+		for _, occ := range t.Occurrences {
+			out = append(out, span(occ.Start, occ.End))
+		}
+		return out
+	}
+	return [][2]time.Time{span(t.Start, t.End)}
+}
+
+// analyzeResources 统计各资源每个工作日的峰值负荷，超出容量的连续日期合并为一条过载警告。
+func analyzeResources(m *Model, loc *time.Location) {
+	m.Loads = nil
+	keyOf := func(name string) string { return strings.ToLower(name) }
+	capacity := make(map[string]int)
+	for _, r := range m.Resources {
+		capacity[keyOf(r.Name)] = r.Capacity
+	}
+	spans := make(map[string][]loadSpan)
+	var order []string
+	for si := range m.Sections {
+		for ti := range m.Sections[si].Tasks {
+			t := &m.Sections[si].Tasks[ti]
+			if t.IsVertical {
+				continue
+			}
+			for _, a := range taskAssignments(t) {
+				key := keyOf(a.resource)
+				if _, ok := capacity[key]; !ok {
+					capacity[key] = defaultCapacity
+					m.Resources = append(m.Resources, Resource{Name: a.resource, Capacity: defaultCapacity})
+				}
+				if _, ok := spans[key]; !ok {
+					order = append(order, key)
+					spans[key] = nil
+				}
+				for _, s := range taskSpans(t) {
+					spans[key] = append(spans[key], loadSpan{start: s[0].In(loc), end: s[1].In(loc), units: a.units, task: t})
+				}
+			}
+		}
+	}
+
+	for _, key := range order {
+		list := spans[key]
+		if len(list) == 0 {
+			continue
+		}
+		name := resourceName(m.Resources, key)
+		first, last := list[0].start, list[0].end
+		for _, s := range list[1:] {
+			if s.start.Before(first) {
+				first = s.start
+			}
+			if s.end.After(last) {
+				last = s.end
+			}
+		}
+		var over []ResourceLoad
+		flush := func() {
+			if len(over) > 0 {
+				m.Warnings = append(m.Warnings, overAllocationWarning(name, capacity[key], over))
+				over = nil
+			}
+		}
+		for day := dayStart(first, loc); day.Before(last); day = startOfNextDay(day) {
+			if !m.Calendar.IsWorkingDay(day) {
+				continue
+			}
+			load := dailyLoad(name, day, list)
+			if load.Load == 0 {
+				flush()
+				continue
+			}
+			m.Loads = append(m.Loads, load)
+			if load.Load <= capacity[key] {
+				flush()
+				continue
+			}
+			if len(over) > 0 && !sameIDs(over[0].TaskIDs, load.TaskIDs) {
+				flush()
+			}
+			over = append(over, load)
+		}
+		flush()
+	}
+}
+
+// dailyLoad 计算资源在 day 当天的峰值并发负荷及参与任务。
+func dailyLoad(name string, day time.Time, list []loadSpan) ResourceLoad {
+	next := startOfNextDay(day)
+	type event struct {
+		at    time.Time
+		delta int
+	}
+	var events []event
+	load := ResourceLoad{Resource: name, Date: day}
+	for _, s := range list {
+		start, end := s.start, s.end
+		if !start.Before(next) || !end.After(day) {
+			continue
+		}
+		if start.Before(day) {
+			start = day
+		}
+		if end.After(next) {
+			end = next
+		}
+		events = append(events, event{start, s.units}, event{end, -s.units})
+		if !containsString(load.TaskIDs, s.task.ID) {
+			load.TaskIDs = append(load.TaskIDs, s.task.ID)
+		}
+	}
+	// 同一时刻先结束后开始，首尾相接的任务不算重叠
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].at.Equal(events[j].at) {
+			return events[i].delta < events[j].delta
+		}
+		return events[i].at.Before(events[j].at)
+	})
+	cur := 0
+	for _, e := range events {
+		cur += e.delta
+		if cur > load.Load {
+			load.Load = cur
+		}
+	}
+	return load
+}
+
+func overAllocationWarning(name string, capacity int, days []ResourceLoad) Warning {
+	peak := 0
+	for _, d := range days {
+		if d.Load > peak {
+			peak = d.Load
+		}
+	}
+	start, end := days[0].Date, days[len(days)-1].Date
+	when := formatDay(start)
+	if !sameDay(start, end) {
+		when += ".." + formatDay(end)
+	}
+	return Warning{
+		Kind:     WarningOverAllocated,
+		TaskIDs:  days[0].TaskIDs,
+		Date:     start,
+		End:      end,
+		Resource: name,
+		Message:  fmt.Sprintf("resource %s is over-allocated on %s: %d%% of %d%% (%s)", name, when, peak, capacity, strings.Join(days[0].TaskIDs, ", ")),
+	}
+}
+
+func resourceName(resources []Resource, key string) string {
+	for _, r := range resources {
+		if strings.ToLower(r.Name) == key {
+			return r.Name
+		}
+	}
+	return key
+}
+
+func sameIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
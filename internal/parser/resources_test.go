@@ -0,0 +1,59 @@
+package parser
+
+import "testing"
+
+func TestSchedule_ResourceOverAllocation(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+resource bob 50%
+section API
+Build :a1, 2025-03-03, 3d, alice
+Review :r1, 2025-03-10, 2d, bob
+section Web
+Screens :w1, 2025-03-04, 2d, alice
+Polish :p1, 2025-03-07, 1d, alice
+Docs :d1, 2025-03-11, 1d, carol
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if len(m.Resources) != 1 || m.Resources[0].Capacity != 50 {
+		t.Fatalf("expected declared bob at 50%%, got %+v", m.Resources)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	var over []Warning
+	for _, w := range m.Warnings {
+		if w.Kind == WarningOverAllocated {
+			over = append(over, w)
+		}
+	}
+	if len(over) != 2 {
+		t.Fatalf("expected 2 over-allocation warnings, got %v", m.Warnings)
+	}
+	alice := over[0]
+	if alice.Resource != "alice" || formatDay(alice.Date) != "2025-03-04" || formatDay(alice.End) != "2025-03-05" {
+		t.Fatalf("unexpected alice warning: %+v", alice)
+	}
+	if len(alice.TaskIDs) != 2 || alice.TaskIDs[0] != "a1" || alice.TaskIDs[1] != "w1" {
+		t.Fatalf("expected conflicting tasks a1, w1, got %v", alice.TaskIDs)
+	}
+	// bob 容量 50%，单个任务即已过载
+	bob := over[1]
+	if bob.Resource != "bob" || formatDay(bob.Date) != "2025-03-10" || formatDay(bob.End) != "2025-03-11" {
+		t.Fatalf("unexpected bob warning: %+v", bob)
+	}
+	peak := 0
+	for _, l := range m.Loads {
+		if l.Resource == "alice" && l.Load > peak {
+			peak = l.Load
+		}
+	}
+	if peak != 200 {
+		t.Fatalf("expected alice peak load 200%%, got %d", peak)
+	}
+}
@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// SectionRollup 为 section 的汇总：任务最早开始到最晚完成，及按工期加权的进度；分组包含其下级 section 的任务。
+type SectionRollup struct {
+	Name     string
+	Parent   string // 上级分组的完整名称，顶层为空
+	Level    int    // 嵌套深度，顶层为 0
+	Start    time.Time
+	End      time.Time
+	Progress int // 0-100，按工作日工期加权；全部为里程碑时按任务数平均
+	Tasks    int // 参与汇总的任务数（不含垂直标记）
+	Done     int // done 状态或进度 100% 的任务数
+}
+
+// RollupSections 按源中顺序返回每个 section 的汇总，空 section 的起止为零值。
+func RollupSections(m Model) []SectionRollup {
+	loc := m.Calendar.Location()
+	out := make([]SectionRollup, 0, len(m.Sections))
+	for si, sec := range m.Sections {
+		r := SectionRollup{Name: sec.Name, Level: sec.Level()}
+		if sec.Level() > 0 {
+			r.Parent = strings.Join(sec.Path[:sec.Level()], sectionPathSep)
+		}
+		var weight, earned, plain float64
+		for _, t := range sectionTasks(m, si) {
+			if t.IsVertical || t.Start.IsZero() {
+				continue
+			}
+			r.Tasks++
+			if r.Start.IsZero() || t.Start.Before(r.Start) {
+				r.Start = t.Start
+			}
+			if t.End.After(r.End) {
+				r.End = t.End
+			}
+			complete := float64(t.Progress) / maxProgressPercent
+			if t.Status == StatusDone || t.Progress >= maxProgressPercent {
+				complete = 1
+				r.Done++
+			}
+			w := taskWorkDays(&t, m.Calendar, loc)
+			weight += w
+			earned += w * complete
+			plain += complete
+		}
+		switch {
+		case weight > 0:
+			r.Progress = int(math.Round(earned / weight * maxProgressPercent))
+		case r.Tasks > 0:
+			r.Progress = int(math.Round(plain / float64(r.Tasks) * maxProgressPercent))
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// sectionTasks 返回第 i 个 section 及其下级分组中的全部任务。
+func sectionTasks(m Model, i int) []Task {
+	var out []Task
+	for _, si := range SectionSubtree(m.Sections, i) {
+		out = append(out, m.Sections[si].Tasks...)
+	}
+	return out
+}
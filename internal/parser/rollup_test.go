@@ -0,0 +1,42 @@
+package parser
+
+import "testing"
+
+func TestRollupSections(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+section Build
+Design :d1, 2025-03-03, 5d, done
+Code   :c1, after d1, 10d, 20%
+section Release
+Ship   :milestone, s1, after c1, 0d, done
+section Empty
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	rs := RollupSections(m)
+	if len(rs) != 3 {
+		t.Fatalf("expected 3 sections, got %+v", rs)
+	}
+	// (5×100% + 10×20%) / 15 ≈ 47%
+	b := rs[0]
+	if b.Tasks != 2 || b.Done != 1 || b.Progress != 47 {
+		t.Fatalf("unexpected build rollup: %+v", b)
+	}
+	if b.Start.Format("2006-01-02") != "2025-03-03" || b.End.Format("2006-01-02") != "2025-03-21" {
+		t.Fatalf("unexpected build span: %s - %s", b.Start, b.End)
+	}
+	if r := rs[1]; r.Tasks != 1 || r.Done != 1 || r.Progress != 100 {
+		t.Fatalf("milestone-only section should roll up by count: %+v", r)
+	}
+	if e := rs[2]; e.Tasks != 0 || !e.Start.IsZero() || e.Progress != 0 {
+		t.Fatalf("empty section should be zero: %+v", e)
+	}
+}
@@ -7,6 +7,8 @@ import (
 )
 
 const (
+	maxCalendarIterations = 3660
+
 	hoursPerDay   = 24
 	daysPerWeek   = 7
 	hoursPerWeek  = daysPerWeek * hoursPerDay
@@ -16,6 +18,7 @@ const (
 // ResolveSchedule 解析依赖并计算起止时间与持续天数。
 // nolint:gocyclo // 核心逻辑复杂，计划按 refactor-design 拆分
 func ResolveSchedule(m Model) (Model, error) {
+	m.Warnings = nil
 	taskMap := make(map[string]*Task)
 	for si := range m.Sections {
 		for ti := range m.Sections[si].Tasks {
@@ -23,12 +26,13 @@ func ResolveSchedule(m Model) (Model, error) {
 			if task.IsVertical {
 				continue
 			}
-			if _, exists := taskMap[task.ID]; exists {
+			if first, exists := taskMap[task.ID]; exists {
 				orig := task.ID
 				suffix := 1
 				for {
 					candidate := fmt.Sprintf("%s_%d", orig, suffix)
 					if _, exists := taskMap[candidate]; !exists {
+						m.Warnings = append(m.Warnings, duplicateIDWarning(first, task, candidate))
 						task.ID = candidate
 						task.ExplicitID = false
 						taskMap[task.ID] = task
@@ -41,6 +45,12 @@ func ResolveSchedule(m Model) (Model, error) {
 			taskMap[task.ID] = task
 		}
 	}
+	if err := expandGroupDependencies(&m, taskMap); err != nil {
+		return Model{}, err
+	}
+	if err := checkDependencies(m, taskMap); err != nil {
+		return Model{}, err
+	}
 
 	loc := time.UTC
 	if m.Calendar.Timezone != "" {
@@ -48,7 +58,11 @@ func ResolveSchedule(m Model) (Model, error) {
 			loc = tz
 		}
 	}
+	if err := resolveRelativeDates(&m, loc); err != nil {
+		return Model{}, err
+	}
 	baseStart := baselineStart(m, loc)
+	status := StatusDate(m)
 
 	visited := make(map[string]bool)
 	resolving := make(map[string]bool)
@@ -67,15 +81,22 @@ func ResolveSchedule(m Model) (Model, error) {
 		}
 	}
 
+	var stack []*Task // 当前解析链，用于报告完整的循环路径
 	var resolve func(*Task) error
 	resolve = func(t *Task) error {
 		if visited[t.ID] {
 			return nil
 		}
 		if resolving[t.ID] {
-			return ParseError{Line: t.Line, Column: t.Column, Message: fmt.Sprintf("circular dependency around %s", t.ID)}
+			return cycleError(stack, t)
 		}
 		resolving[t.ID] = true
+		stack = append(stack, t)
+		defer func() { stack = stack[:len(stack)-1] }()
+		if t.Effort.Value > 0 && !t.IsMilestone && !(t.HasStart && t.HasEnd) {
+			t.Duration = effortDuration(t)
+			t.DurationExplicit = true
+		}
 
 		isTimeTask := t.HasTime || t.Duration.Unit == DurationMinute || t.Duration.Unit == DurationHour
 
@@ -108,6 +129,9 @@ func ResolveSchedule(m Model) (Model, error) {
 
 		beforeStarts := []time.Time{}
 		maxAfter := time.Time{}
+		// SS 约束开始下界，FF/SF 约束结束下界
+		var startBound, endBound time.Time
+		var endBoundDep Dependency
 		for _, dep := range t.Dependencies {
 			target, ok := taskMap[dep.Target]
 			if !ok {
@@ -118,27 +142,45 @@ func ResolveSchedule(m Model) (Model, error) {
 			}
 			switch dep.Type {
 			case DepAfter:
+				var candidate time.Time
 				if isTimeTask || target.HasTime || target.Duration.Unit == DurationMinute || target.Duration.Unit == DurationHour {
-					if candidate := target.End.Add(time.Nanosecond); candidate.After(maxAfter) {
-						maxAfter = candidate
-					}
+					candidate = target.End.Add(time.Nanosecond)
 				} else {
-					if candidate := startOfNextDay(target.End); candidate.After(maxAfter) {
-						maxAfter = candidate
-					}
+					candidate = startOfNextDay(target.End)
+				}
+				if candidate = applyLag(candidate, dep.Lag, m.Calendar); candidate.After(maxAfter) {
+					maxAfter = candidate
 				}
 			case DepBefore:
 				// 结束不晚于目标开始；选取更靠后的可行起点以贴近约束
 				if target.Start.IsZero() {
 					continue
 				}
-				startCandidate := target.Start.Add(-durationToDuration(t.Duration))
+				boundary := applyLag(target.Start, DurationSpec{Value: -dep.Lag.Value, Unit: dep.Lag.Unit}, m.Calendar)
+				startCandidate := boundary.Add(-durationToDuration(t.Duration))
+				if t.Duration.Unit == DurationMonth {
+					startCandidate = AddMonths(boundary, -t.Duration.Value)
+				}
 				if !t.HasStart { // 只有在未显式指定开始时间时才调整起点
 					if start.IsZero() || start.Before(startCandidate) {
 						start = startCandidate
 					}
 				}
-				beforeStarts = append(beforeStarts, target.Start)
+				beforeStarts = append(beforeStarts, boundary)
+			case DepStartStart:
+				if candidate := applyLag(target.Start, dep.Lag, m.Calendar); candidate.After(startBound) {
+					startBound = candidate
+				}
+			case DepFinishFinish, DepStartFinish:
+				candidate := applyLag(target.End, dep.Lag, m.Calendar)
+				if dep.Type == DepStartFinish {
+					// 目标开始前一刻即可完成
+					candidate = applyLag(target.Start, dep.Lag, m.Calendar).Add(-time.Nanosecond)
+				}
+				if candidate.After(endBound) {
+					endBound = candidate
+					endBoundDep = dep
+				}
 			}
 		}
 
@@ -152,6 +194,12 @@ func ResolveSchedule(m Model) (Model, error) {
 			}
 			// 结束在依赖开始日的前一日
 			customEnd := time.Date(minBefore.Year(), minBefore.Month(), minBefore.Day(), 0, 0, 0, 0, minBefore.Location()).Add(-time.Nanosecond)
+			if endBound.After(customEnd) {
+				return unsatisfiedDependency(t, endBoundDep, customEnd, endBound)
+			}
+			if startBound.After(customEnd) {
+				return ParseError{Line: t.Line, Column: t.Column, Message: fmt.Sprintf("dependencies of %s cannot be satisfied: start bound %s is after required end %s", t.ID, formatDay(startBound), formatDay(customEnd))}
+			}
 			span := int(customEnd.Sub(start).Hours()/hoursPerDay) + 1
 			if span <= 0 {
 				span = 1
@@ -162,6 +210,9 @@ func ResolveSchedule(m Model) (Model, error) {
 			t.End = customEnd
 			t.HasEnd = true
 			t.DurationDays = span
+			if m.Reforecast {
+				t.Start, t.End, t.DurationDays = reforecastTask(t, t.Start, t.End, t.DurationDays, status, m.Calendar, true)
+			}
 			visited[t.ID] = true
 			resolving[t.ID] = false
 			return nil
@@ -179,7 +230,7 @@ func ResolveSchedule(m Model) (Model, error) {
 				}
 				prevEnd := prev.End
 				if prevEnd.IsZero() {
-					prevEnd = prev.Start.Add(durationToDuration(prev.Duration))
+					prevEnd = prev.Start.Add(durationFrom(prev.Start, prev.Duration))
 				}
 				if isTimeTask || prev.HasTime || prev.Duration.Unit == DurationMinute || prev.Duration.Unit == DurationHour {
 					start = prevEnd.Add(time.Nanosecond)
@@ -195,9 +246,29 @@ func ResolveSchedule(m Model) (Model, error) {
 		if !maxAfter.IsZero() && (start.IsZero() || maxAfter.After(start)) {
 			start = maxAfter
 		}
+		if startBound.After(start) {
+			start = startBound
+		}
+		depStart := maxAfter
+		if startBound.After(depStart) {
+			depStart = startBound
+		}
+		if !endBound.IsZero() && !t.HasStart { // 显式开始不被 FF/SF 移动，结束不足时在下方报错
+			if candidate := startForEnd(endBound, t.Duration, m.Calendar, isTimeTask); candidate.After(start) {
+				start = candidate
+				depStart = candidate
+			}
+		}
+		start = applyStartConstraints(t, start, depStart, loc, &m.Warnings)
 
 		// 显式起止日期：直接使用，不通过 applyCalendar 跳过周末
 		if t.HasStart && t.HasEnd {
+			if endBound.After(t.End) {
+				return unsatisfiedDependency(t, endBoundDep, t.End, endBound)
+			}
+			if start.After(t.End) {
+				return ParseError{Line: t.Line, Column: t.Column, Message: fmt.Sprintf("dependencies of %s cannot be satisfied: start %s is after fixed end %s", t.ID, formatDay(start), formatDay(t.End))}
+			}
 			t.Start = start
 			startDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
 			endDay := time.Date(t.End.Year(), t.End.Month(), t.End.Day(), 0, 0, 0, 0, t.End.Location())
@@ -206,21 +277,31 @@ func ResolveSchedule(m Model) (Model, error) {
 				days = 1
 			}
 			t.DurationDays = days
+			if m.Reforecast {
+				t.Start, t.End, t.DurationDays = reforecastTask(t, t.Start, t.End, t.DurationDays, status, m.Calendar, true)
+			}
 			visited[t.ID] = true
 			resolving[t.ID] = false
 			return nil
 		}
 
-		origDur := t.Duration
-		end, days := applyCalendar(start, t.Duration, m.Calendar)
-		// 对周/月等单位使用天数期望，避免包容端偏差
-		if origDur.Unit == DurationWeek && origDur.Value > 0 {
-			days = origDur.Value * daysPerWeek
-			end = start.Add(durationToDuration(DurationSpec{Value: days, Unit: DurationDay}) - time.Nanosecond)
+		if usesWorkHours(t.Duration, m.Calendar) {
+			// 按工作时段排程时，起点落在下班或排除日则顺延到下一工作时刻
+			start = m.Calendar.NextWorkingTime(start)
+		}
+		end, days := scheduleEnd(start, t.Duration, m.Calendar)
+		if t.HasStart && endBound.After(end) {
+			return unsatisfiedDependency(t, endBoundDep, end, endBound)
+		}
+		if m.Reforecast {
+			start, end, days = reforecastTask(t, start, end, days, status, m.Calendar, false)
 		}
 		t.Start = start
 		t.End = end
 		t.DurationDays = days
+		if t.Recurrence != nil {
+			expandRecurrence(t, start, m.Calendar)
+		}
 		visited[t.ID] = true
 		resolving[t.ID] = false
 		return nil
@@ -236,10 +317,65 @@ func ResolveSchedule(m Model) (Model, error) {
 			}
 		}
 	}
+	scheduleBackward(&m, loc)
+	m.ProjectStart = earliestStart(m)
+	checkConstraints(&m, loc)
+	analyzeNetwork(&m, loc)
+	analyzeResources(&m, loc)
 
 	return m, nil
 }
 
+// scheduleEnd 计算从 start 开始、按日历排程 dur 后的结束时间与跨越天数。
+func scheduleEnd(start time.Time, dur DurationSpec, cal Calendar) (time.Time, int) {
+	end, days := applyCalendar(start, dur, cal)
+	// 对周/月等单位使用天数期望，避免包容端偏差
+	if dur.Unit == DurationWeek && dur.Value > 0 {
+		days = dur.Value * daysPerWeek
+		end = start.Add(durationToDuration(DurationSpec{Value: days, Unit: DurationDay}) - time.Nanosecond)
+	}
+	return end, days
+}
+
+// startForEnd 返回使任务结束不早于 endBound 的最早开始时间。
+func startForEnd(endBound time.Time, dur DurationSpec, cal Calendar, timeBased bool) time.Time {
+	start := endBound.Add(-durationToDuration(dur))
+	if dur.Unit == DurationMonth {
+		start = AddMonths(endBound, -dur.Value)
+	}
+	if !timeBased {
+		start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	}
+	if dur.Value <= 0 {
+		return start
+	}
+	// 排除日只会让结束更晚，逐步前移起点直至满足
+	for i := 0; i < maxCalendarIterations; i++ {
+		end, _ := scheduleEnd(start, dur, cal)
+		if !end.Before(endBound) {
+			return start
+		}
+		if timeBased {
+			start = start.Add(endBound.Sub(end))
+		} else {
+			start = start.AddDate(0, 0, 1)
+		}
+	}
+	return start
+}
+
+func unsatisfiedDependency(t *Task, dep Dependency, end, required time.Time) error {
+	return ParseError{
+		Line:    t.Line,
+		Column:  t.Column,
+		Message: fmt.Sprintf("dependency %q of %s cannot be satisfied: end %s is before required %s", dep.String(), t.ID, formatDay(end), formatDay(required)),
+	}
+}
+
+func formatDay(t time.Time) string {
+	return t.Format(defaultDateLayout)
+}
+
 func baselineStart(m Model, loc *time.Location) time.Time {
 	var min time.Time
 	add := func(t time.Time) {
@@ -262,7 +398,7 @@ func baselineStart(m Model, loc *time.Location) time.Time {
 		if v.HasStart {
 			end := v.Start.In(loc)
 			if v.Duration.Value > 0 {
-				end = end.Add(durationToDuration(v.Duration))
+				end = end.Add(durationFrom(end, v.Duration))
 				if v.Duration.Unit == DurationMinute || v.Duration.Unit == DurationHour {
 					end = end.Add(time.Minute)
 				}
@@ -271,12 +407,32 @@ func baselineStart(m Model, loc *time.Location) time.Time {
 		}
 	}
 	if min.IsZero() {
-		min = time.Now().In(loc)
+		min = renderClock(m, loc)
 		min = time.Date(min.Year(), min.Month(), min.Day(), 0, 0, 0, 0, loc)
 	}
 	return min
 }
 
+// AddMonths 按自然月偏移 t，目标月没有对应日期时取该月最后一天（1 月 31 日加一个月为 2 月末）。
+func AddMonths(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	first := time.Date(y, m+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); d > last {
+		d = last
+	}
+	return first.AddDate(0, 0, d-1)
+}
+
+// durationFrom 返回从 start 起 d 所跨越的时长：月按自然月计算，其余单位为固定长度。
+func durationFrom(start time.Time, d DurationSpec) time.Duration {
+	if d.Unit == DurationMonth {
+		return AddMonths(start, d.Value).Sub(start)
+	}
+	return durationToDuration(d)
+}
+
+// durationToDuration 将持续时间折算为固定长度；缺少起点时月按 30 天近似，
+// 有起点时应使用 durationFrom。
 func durationToDuration(d DurationSpec) time.Duration {
 	switch d.Unit {
 	case DurationMinute:
@@ -295,48 +451,26 @@ func durationToDuration(d DurationSpec) time.Duration {
 }
 
 func applyCalendar(start time.Time, dur DurationSpec, cal Calendar) (time.Time, int) {
-	loc := time.UTC
-	if cal.Timezone != "" {
-		if tz, err := time.LoadLocation(cal.Timezone); err == nil {
-			loc = tz
-		}
-	}
-	start = start.In(loc)
+	start = start.In(cal.Location())
 	if dur.Value <= 0 {
 		return start, 0
 	}
-
-	remaining := durationToDuration(dur)
-	current := start
-	timeBased := dur.Unit == DurationMinute || (dur.Unit == DurationHour && dur.Value < hoursPerDay)
-
-	// 跳过起始日若为排除日
-	for shouldSkipDay(current, cal) {
-		current = startOfNextDay(current)
-		start = current
+	if usesWorkHours(dur, cal) {
+		start = cal.NextWorkingTime(start)
+		end := cal.AddWorkingDuration(start, durationToDuration(dur))
+		return end, inclusiveSpanDays(start, end)
 	}
 
-	for remaining > 0 {
-		if shouldSkipDay(current, cal) {
-			current = startOfNextDay(current)
-			continue
-		}
-		endOfDay := startOfNextDay(current)
-		span := endOfDay.Sub(current)
-		if remaining <= span {
-			// 将结束定位在当前工作区间内
-			if timeBased {
-				current = current.Add(remaining)
-			} else {
-				current = current.Add(remaining - time.Nanosecond)
-			}
-			break
-		}
-		remaining -= span
-		current = endOfDay
+	// 日级以上时长按整日消耗，不受工作时段限制；起始日若为排除日则顺延
+	cal.WorkHours = WorkHours{}
+	start = cal.NextWorkingDay(start)
+	end := cal.AddWorkingDuration(start, durationFrom(start, dur))
+	timeBased := dur.Unit == DurationMinute || (dur.Unit == DurationHour && dur.Value < hoursPerDay)
+	if !timeBased {
+		// 日级结束落在最后一个工作日之内
+		end = end.Add(-time.Nanosecond)
 	}
 
-	end := current
 	startDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
 	endDay := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location())
 	days := int(endDay.Sub(startDay).Hours()/hoursPerDay) + 1
@@ -346,6 +480,17 @@ func applyCalendar(start time.Time, dur DurationSpec, cal Calendar) (time.Time,
 	return end, days
 }
 
+// applyLag 按依赖延迟移动时间点：天单位按工作日计数（跳过排除日），其余单位按日历时间。
+func applyLag(t time.Time, lag DurationSpec, cal Calendar) time.Time {
+	if lag.Value == 0 {
+		return t
+	}
+	if lag.Unit == DurationDay {
+		return cal.AddWorkingDays(t, lag.Value)
+	}
+	return t.Add(durationFrom(t, lag))
+}
+
 func startOfNextDay(t time.Time) time.Time {
 	y, m, d := t.Date()
 	return time.Date(y, m, d, 0, 0, 0, 0, t.Location()).AddDate(0, 0, 1)
@@ -363,35 +508,6 @@ func findPrevInSection(m Model, t *Task) *Task {
 	return nil
 }
 
-func shouldSkipDay(t time.Time, cal Calendar) bool {
-	if cal.ExcludeWeekend && isWeekendDay(t, cal) {
-		for _, d := range cal.IncludeDates {
-			if sameDay(t, d) {
-				return false
-			}
-		}
-		return true
-	}
-	for _, d := range cal.ExcludeDates {
-		if sameDay(t, d) {
-			return true
-		}
-	}
-	return false
-}
-
-func isWeekendDay(t time.Time, cal Calendar) bool {
-	if len(cal.WeekendDays) == 0 {
-		return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
-	}
-	for _, wd := range cal.WeekendDays {
-		if t.Weekday() == wd {
-			return true
-		}
-	}
-	return false
-}
-
 func sameDay(a, b time.Time) bool {
 	ay, am, ad := a.Date()
 	by, bm, bd := b.Date()
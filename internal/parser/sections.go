@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// sectionPathSep 分隔嵌套 section 的各级名称，如 `section Program / Backend / API`。
+const sectionPathSep = " / "
+
+// addSection 解析 section 名称并追加到模型：嵌套路径中尚未打开的上级分组会先以空 section 插入，
+// 使分组在图中拥有自己的标题行。分组的下级须连续书写，已关闭的分组再次出现时返回错误。
+// 返回写入任务的完整名称。
+func addSection(name string, lineNo int, model *Model) (string, error) {
+	var path []string
+	for _, part := range strings.Split(name, sectionPathSep) {
+		if part = strings.TrimSpace(part); part != "" {
+			path = append(path, part)
+		}
+	}
+	if len(path) == 0 {
+		model.Sections = append(model.Sections, Section{Name: name})
+		return name, nil
+	}
+	var open []string
+	if n := len(model.Sections); n > 0 {
+		open = model.Sections[n-1].Path
+	}
+	for depth := 1; depth <= len(path); depth++ {
+		if hasPathPrefix(open, path[:depth]) {
+			continue
+		}
+		if group := closedGroup(model.Sections, path[:depth], len(path) > 1); group != "" {
+			return "", newParseError(lineNo, 1, fmt.Sprintf("section %s reopens group %s; keep its subsections together", strings.Join(path, sectionPathSep), group))
+		}
+		if depth < len(path) {
+			model.Sections = append(model.Sections, Section{Name: strings.Join(path[:depth], sectionPathSep), Path: path[:depth:depth]})
+		}
+	}
+	full := strings.Join(path, sectionPathSep)
+	model.Sections = append(model.Sections, Section{Name: full, Path: path})
+	return full, nil
+}
+
+// closedGroup 返回已出现过、路径以 prefix 开头的分组名称；nested 为 false 时仅同名的平级 section
+// 重复出现（未嵌套的旧写法）不视为重开分组。
+func closedGroup(secs []Section, prefix []string, nested bool) string {
+	for _, sec := range secs {
+		if hasPathPrefix(sec.Path, prefix) && (nested || len(sec.Path) > len(prefix)) {
+			return strings.Join(prefix, sectionPathSep)
+		}
+	}
+	return ""
+}
+
+// hasPathPrefix 判断 path 是否以 prefix 开头。
+func hasPathPrefix(path, prefix []string) bool {
+	return len(prefix) > 0 && len(path) >= len(prefix) && slices.Equal(path[:len(prefix)], prefix)
+}
+
+// Level 返回 section 的嵌套深度，顶层为 0。
+func (s Section) Level() int {
+	if len(s.Path) == 0 {
+		return 0
+	}
+	return len(s.Path) - 1
+}
+
+// Label 返回 section 本级名称（路径的最后一段），用于缩进显示。
+func (s Section) Label() string {
+	if len(s.Path) == 0 {
+		return s.Name
+	}
+	return s.Path[len(s.Path)-1]
+}
+
+// Encloses 判断 o 的路径是否与 s 相同或位于 s 之下。
+func (s Section) Encloses(o Section) bool {
+	return hasPathPrefix(o.Path, s.Path)
+}
+
+// SectionSubtree 返回第 i 个 section 及路径位于其下的全部下级分组的下标（按路径前缀而非位置收集）。
+func SectionSubtree(secs []Section, i int) []int {
+	out := []int{i}
+	for j := range secs {
+		if j != i && len(secs[j].Path) > len(secs[i].Path) && secs[i].Encloses(secs[j]) {
+			out = append(out, j)
+		}
+	}
+	return out
+}
+
+// groupKey 将 section 名称规范为依赖引用形式：各级以 `/` 相连且不含空格，如 Backend/API。
+func groupKey(name string) string {
+	return strings.ReplaceAll(name, sectionPathSep, "/")
+}
+
+// expandGroupDependencies 将指向分组（section 名称）的依赖展开为对其下全部任务（含下级分组）的依赖：
+// `after Backend` 即在整组完成后开始，`before Backend` 即在整组开始前完成。任务 ID 优先于分组名；
+// 任务不能依赖自身所在的分组。
+func expandGroupDependencies(m *Model, taskMap map[string]*Task) error {
+	// 同名 section 可能出现多次（未嵌套的旧写法），依赖其名称即依赖全部同名 section 及其下级
+	groups := make(map[string][]int)
+	for si, sec := range m.Sections {
+		if sec.Name == "" {
+			continue
+		}
+		groups[groupKey(sec.Name)] = append(groups[groupKey(sec.Name)], si)
+	}
+	for si := range m.Sections {
+		for ti := range m.Sections[si].Tasks {
+			t := &m.Sections[si].Tasks[ti]
+			var deps []Dependency
+			for _, dep := range t.Dependencies {
+				gs, ok := groups[dep.Target]
+				if _, isTask := taskMap[dep.Target]; isTask || !ok {
+					deps = append(deps, dep)
+					continue
+				}
+				var members []int
+				for _, gi := range gs {
+					for _, mi := range SectionSubtree(m.Sections, gi) {
+						if !slices.Contains(members, mi) {
+							members = append(members, mi)
+						}
+					}
+				}
+				slices.Sort(members)
+				if slices.Contains(members, si) {
+					return ParseError{Line: t.Line, Column: t.Column, Message: fmt.Sprintf("task %s cannot depend on its own section %s", t.ID, dep.Target)}
+				}
+				for _, mi := range members {
+					for _, member := range m.Sections[mi].Tasks {
+						if member.IsVertical {
+							continue
+						}
+						expanded := dep
+						expanded.Target = member.ID
+						deps = append(deps, expanded)
+					}
+				}
+			}
+			t.Dependencies = deps
+		}
+	}
+	return nil
+}
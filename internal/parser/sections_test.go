@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParse_NestedSections(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+section Backend / API
+Auth :a1, 2025-03-03, 3d
+section Backend / Storage
+Schema :s1, 2025-03-03, 5d
+section Release
+Ship :r1, after Backend, 1d
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	var names []string
+	for _, sec := range m.Sections {
+		names = append(names, sec.Name)
+	}
+	// 上级分组 Backend 自动插入，Storage 与 API 同属一个分组
+	if strings.Join(names, "|") != "Backend|Backend / API|Backend / Storage|Release" {
+		t.Fatalf("unexpected sections: %q", names)
+	}
+	if api := m.Sections[1]; api.Level() != 1 || api.Label() != "API" || api.Tasks[0].Section != "Backend / API" {
+		t.Fatalf("unexpected nested section: %+v", api)
+	}
+	if sub := SectionSubtree(m.Sections, 0); len(sub) != 3 {
+		t.Fatalf("expected Backend to enclose 2 subsections, got %v", sub)
+	}
+
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	// after Backend 等价于依赖组内全部任务：s1 于 03-07 完成
+	r1 := m.Sections[3].Tasks[0]
+	if r1.Start.Format("2006-01-02") != "2025-03-08" || len(r1.Dependencies) != 2 {
+		t.Fatalf("expected group dependency to expand, got %s %+v", r1.Start, r1.Dependencies)
+	}
+	rs := RollupSections(m)
+	if rs[0].Tasks != 2 || rs[0].End.Format("2006-01-02") != "2025-03-07" || rs[1].Parent != "Backend" || rs[1].Level != 1 {
+		t.Fatalf("unexpected group rollup: %+v", rs[:2])
+	}
+}
+
+func TestResolveSchedule_OwnSectionDependency(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+section Backend / API
+Auth :a1, 2025-03-03, 3d
+Token :a2, after Backend, 2d
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if _, err := ResolveSchedule(m); err == nil || !strings.Contains(err.Error(), "own section Backend") {
+		t.Fatalf("expected own-section error, got %v", err)
+	}
+}
+
+func TestParse_ReopenedSectionGroup(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+section Backend / API
+Auth :a1, 2025-03-03, 3d
+section Frontend
+UI :f1, 2025-03-03, 2d
+section Backend / Storage
+Schema :s1, 2025-03-03, 8d
+section Release
+Ship :r1, after Backend, 1d
+`
+	_, err := Parse(src)
+	var perr ParseError
+	if !errors.As(err, &perr) || perr.Line != 7 || !strings.Contains(perr.Message, "reopens group Backend") {
+		t.Fatalf("expected reopened group to be rejected on line 7, got %v", err)
+	}
+
+	// 平级同名 section 重复出现仍然允许
+	if _, err := Parse("gantt\nsection A\nX :x1, 2025-03-03, 1d\nsection B\nY :y1, 2025-03-03, 1d\nsection A\nZ :z1, 2025-03-03, 1d\n"); err != nil {
+		t.Fatalf("flat duplicate sections should parse: %v", err)
+	}
+}
+
+func TestSectionSubtree_ByPathPrefix(t *testing.T) {
+	secs := []Section{
+		{Name: "Backend", Path: []string{"Backend"}},
+		{Name: "Backend / API", Path: []string{"Backend", "API"}},
+		{Name: "Frontend", Path: []string{"Frontend"}},
+		{Name: "Backend / Storage", Path: []string{"Backend", "Storage"}},
+	}
+	if got := SectionSubtree(secs, 0); !slices.Equal(got, []int{0, 1, 3}) {
+		t.Fatalf("expected members by path prefix, got %v", got)
+	}
+}
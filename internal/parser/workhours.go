@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const minutesPerHour = 60
+
+var clockRangeRe = regexp.MustCompile(`^([0-9]{1,2}):([0-9]{2})-([0-9]{1,2}):([0-9]{2})$`)
+
+// parseWorkHours 解析 workhours 指令：
+//
+//	workhours 09:00-18:00 lunch 12:00-13:00
+//	workhours 09:00-12:00 13:30-18:00
+//	workhours fri 09:00-15:00
+//	workhours sat off
+//
+// 不带星期的行设置默认工作时段，带星期的行覆盖该日；lunch 从当行时段中扣除。
+func parseWorkHours(expr string, lineNo int, model *Model) error {
+	fields := strings.Fields(strings.ReplaceAll(expr, ",", " "))
+	if len(fields) == 0 {
+		return newParseError(lineNo, 1, "workhours needs at least one range such as 09:00-18:00")
+	}
+	var days []time.Weekday
+	for len(fields) > 0 {
+		wd, ok := weekdayFromString(strings.ToLower(fields[0]))
+		if !ok {
+			break
+		}
+		days = append(days, wd)
+		fields = fields[1:]
+	}
+
+	var ranges, breaks []ClockRange
+	off := false
+	for i := 0; i < len(fields); i++ {
+		tok := strings.ToLower(fields[i])
+		switch {
+		case tok == "off":
+			off = true
+		case tok == "lunch" || tok == "break":
+			if i+1 >= len(fields) {
+				return newParseError(lineNo, 1, fmt.Sprintf("%s needs a range such as 12:00-13:00", tok))
+			}
+			r, err := parseClockRange(fields[i+1])
+			if err != nil {
+				return newParseError(lineNo, 1, err.Error())
+			}
+			breaks = append(breaks, r)
+			i++
+		default:
+			r, err := parseClockRange(fields[i])
+			if err != nil {
+				return newParseError(lineNo, 1, err.Error())
+			}
+			ranges = append(ranges, r)
+		}
+	}
+	if off && len(ranges) > 0 {
+		return newParseError(lineNo, 1, "workhours cannot combine off with ranges")
+	}
+	if !off && len(ranges) == 0 {
+		return newParseError(lineNo, 1, "workhours needs at least one range such as 09:00-18:00")
+	}
+	for _, b := range breaks {
+		ranges = subtractClockRange(ranges, b)
+	}
+	ranges = normalizeClockRanges(ranges)
+
+	wh := &model.Calendar.WorkHours
+	if len(days) == 0 {
+		if off {
+			return newParseError(lineNo, 1, "workhours off needs a weekday")
+		}
+		wh.Default = ranges
+		return nil
+	}
+	if wh.ByWeekday == nil {
+		wh.ByWeekday = make(map[time.Weekday][]ClockRange)
+	}
+	for _, wd := range days {
+		wh.ByWeekday[wd] = append([]ClockRange{}, ranges...)
+	}
+	return nil
+}
+
+func parseClockRange(s string) (ClockRange, error) {
+	m := clockRangeRe.FindStringSubmatch(s)
+	if m == nil {
+		return ClockRange{}, fmt.Errorf("invalid work hours range %q", s)
+	}
+	var vals [4]int
+	for i := range vals {
+		v, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return ClockRange{}, fmt.Errorf("invalid work hours range %q", s)
+		}
+		vals[i] = v
+	}
+	r := ClockRange{Start: vals[0]*minutesPerHour + vals[1], End: vals[2]*minutesPerHour + vals[3]}
+	if vals[1] >= minutesPerHour || vals[3] >= minutesPerHour || r.End > hoursPerDay*minutesPerHour || r.Start >= r.End {
+		return ClockRange{}, fmt.Errorf("invalid work hours range %q", s)
+	}
+	return r, nil
+}
+
+// subtractClockRange 从时段列表中扣除 b（如午休）。
+func subtractClockRange(ranges []ClockRange, b ClockRange) []ClockRange {
+	out := make([]ClockRange, 0, len(ranges)+1)
+	for _, r := range ranges {
+		if b.End <= r.Start || b.Start >= r.End {
+			out = append(out, r)
+			continue
+		}
+		if b.Start > r.Start {
+			out = append(out, ClockRange{Start: r.Start, End: b.Start})
+		}
+		if b.End < r.End {
+			out = append(out, ClockRange{Start: b.End, End: r.End})
+		}
+	}
+	return out
+}
+
+// normalizeClockRanges 按开始时刻排序，并合并重叠或首尾相接的时段，避免日历按书写顺序遍历时
+// 跳过较早的时段或重复计时。
+func normalizeClockRanges(ranges []ClockRange) []ClockRange {
+	sorted := append([]ClockRange(nil), ranges...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+	out := make([]ClockRange, 0, len(sorted))
+	for _, r := range sorted {
+		if n := len(out); n > 0 && r.Start <= out[n-1].End {
+			out[n-1].End = max(out[n-1].End, r.End)
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// usesWorkHours 判断持续时间是否按工作时段消耗：仅小时与分钟单位。
+func usesWorkHours(dur DurationSpec, cal Calendar) bool {
+	return cal.WorkHours.Enabled() && (dur.Unit == DurationHour || dur.Unit == DurationMinute)
+}
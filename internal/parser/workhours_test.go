@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSchedule_WorkHours(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD HH:mm
+excludes weekends
+workhours 09:00-18:00 lunch 12:00-13:00
+workhours fri 09:00-15:00
+section Ops
+Migrate :m1, 2025-03-06 10:00, 16h
+Verify :v1, after m1, 2h
+Rollout :r1, 2025-03-07 17:00, 30m
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	const layout = "2006-01-02 15:04"
+	tasks := m.Sections[0].Tasks
+	// 周四 10-12、13-18 共 7h，周五覆盖为 09-15 共 6h（午休只作用于当行），下周一 09-12 补足 3h
+	if got := tasks[0].End.Format(layout); got != "2025-03-10 12:00" {
+		t.Fatalf("m1 end expected 2025-03-10 12:00, got %s", got)
+	}
+	// 前驱在午休开始时结束，后继顺延到午休之后
+	if got := tasks[1].Start.Format(layout); got != "2025-03-10 13:00" {
+		t.Fatalf("v1 start expected 2025-03-10 13:00, got %s", got)
+	}
+	if got := tasks[1].End.Format(layout); got != "2025-03-10 15:00" {
+		t.Fatalf("v1 end expected 2025-03-10 15:00, got %s", got)
+	}
+	// 周五 15:00 下班，17:00 开始的任务顺延到下周一 09:00
+	if got := tasks[2].Start.Format(layout); got != "2025-03-10 09:00" {
+		t.Fatalf("r1 start expected 2025-03-10 09:00, got %s", got)
+	}
+}
+
+func TestParseWorkHours_Invalid(t *testing.T) {
+	for _, line := range []string{"workhours 18:00-09:00", "workhours 09:00-18:00 lunch", "workhours off", "workhours mon 9-17"} {
+		if _, err := Parse("gantt\n" + line + "\nTask :a1, 2025-03-03, 1d"); err == nil {
+			t.Fatalf("expected error for %q", line)
+		}
+	}
+}
+
+func TestParse_WorkHoursOutOfOrder(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD HH:mm
+workhours 13:30-18:00 09:00-12:00 11:00-11:30 11:30-12:00
+section Ops
+Fix :f1, 2025-03-06 08:00, 4h
+`
+	m, err := Parse(src)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	// 按开始时刻排序，重叠与相接的时段合并
+	want := []ClockRange{{Start: 9 * 60, End: 12 * 60}, {Start: 13*60 + 30, End: 18 * 60}}
+	if got := m.Calendar.WorkHours.Default; !slices.Equal(got, want) {
+		t.Fatalf("expected sorted, merged ranges %v, got %v", want, got)
+	}
+	m, err = ResolveSchedule(m)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	// 上午 09-12 计 3h，午后 13:30 起再计 1h
+	f1 := m.Sections[0].Tasks[0]
+	if f1.Start.Format("15:04") != "09:00" || f1.End.Format("15:04") != "14:30" {
+		t.Fatalf("expected 09:00-14:30, got %s-%s", f1.Start.Format("15:04"), f1.End.Format("15:04"))
+	}
+}
@@ -1,6 +1,7 @@
 package render
 
 import (
+	"math"
 	"time"
 
 	"github.com/pyroflux/go-mermaid-gantt/internal/parser"
@@ -11,7 +12,7 @@ const (
 	hoursPerMonth = 30 * hoursPerDay
 )
 
-// DurationHours 将 DurationSpec 转换为小时。
+// DurationHours 将 DurationSpec 转换为小时；月按 30 天近似，有起点时 ApplyCalendar 按自然月计算。
 func DurationHours(d parser.DurationSpec) float64 {
 	if d.Value <= 0 {
 		return 0
@@ -30,74 +31,33 @@ func DurationHours(d parser.DurationSpec) float64 {
 	}
 }
 
-// ApplyCalendar 计算持续时间在日历下的结束时间与天数跨度。
+// ApplyCalendar 计算持续时间在日历下的结束时间与天数跨度，规则与排程一致（calendar.Calendar）。
 // daysSpan 用于渲染时计算宽度（至少 1 天）。
 func ApplyCalendar(start time.Time, dur parser.DurationSpec, cal parser.Calendar) (end time.Time, daysSpan int) {
-	loc := time.UTC
-	if cal.Timezone != "" {
-		if tz, err := time.LoadLocation(cal.Timezone); err == nil {
-			loc = tz
-		}
-	}
-	start = start.In(loc)
+	start = start.In(cal.Location())
 
 	// milestone 或零时长
 	if dur.Value <= 0 {
 		return start, 0
 	}
 
-	hours := DurationHours(dur)
-	if hours == 0 {
+	d := time.Duration(DurationHours(dur) * float64(time.Hour))
+	if dur.Unit == parser.DurationMonth {
+		d = parser.AddMonths(start, dur.Value).Sub(start)
+	}
+	if d <= 0 {
 		return start, 0
 	}
-
-	current := start
-	remaining := hours
-	daysSpan = 0
-
-	for remaining > 0 {
-		// 检查是否跳过当前日
-		if shouldSkipDay(current, cal) {
-			current = nextDay(current)
-			daysSpan++
-			continue
-		}
-
-		if remaining >= hoursPerDay {
-			remaining -= hoursPerDay
-			current = nextDay(current)
-			daysSpan++
-			continue
-		}
-
-		// 剩余不足一天的小时，仍占用当日
-		current = current.Add(time.Duration(remaining * float64(time.Hour)))
-		remaining = 0
-		daysSpan++
+	// 工作时段只约束小时/分钟级时长
+	if dur.Unit != parser.DurationHour && dur.Unit != parser.DurationMinute {
+		cal.WorkHours = parser.WorkHours{}
 	}
-
-	if daysSpan == 0 {
+	end = cal.AddWorkingDuration(start, d)
+	daysSpan = int(math.Ceil(end.Sub(start).Hours() / hoursPerDay))
+	if daysSpan <= 0 {
 		daysSpan = 1
 	}
-	return current, daysSpan
-}
-
-func shouldSkipDay(t time.Time, cal parser.Calendar) bool {
-	if cal.ExcludeWeekend && (t.Weekday() == time.Saturday || t.Weekday() == time.Sunday) {
-		// includeDates 可以强制包含
-		for _, d := range cal.IncludeDates {
-			if sameDay(t, d) {
-				return false
-			}
-		}
-		return true
-	}
-	for _, d := range cal.ExcludeDates {
-		if sameDay(t, d) {
-			return true
-		}
-	}
-	return false
+	return end, daysSpan
 }
 
 func sameDay(a, b time.Time) bool {
@@ -105,7 +65,3 @@ func sameDay(a, b time.Time) bool {
 	by, bm, bd := b.Date()
 	return ay == by && am == bm && ad == bd
 }
-
-func nextDay(t time.Time) time.Time {
-	return t.AddDate(0, 0, 1)
-}
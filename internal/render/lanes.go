@@ -0,0 +1,110 @@
+package render
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pyroflux/go-mermaid-gantt/internal/parser"
+)
+
+// unassignedLaneName 为资源泳道视图中未分配资源任务所在的泳道名。
+const unassignedLaneName = "(unassigned)"
+
+// lane 为绘制时的一组行：section 或资源泳道。
+type lane struct {
+	name  string
+	level int // 嵌套 section 的深度，标题按深度缩进
+	rows  [][]parser.Task
+}
+
+// sectionLanes 按 section 布局，每个任务独占一行。
+func sectionLanes(m parser.Model) []lane {
+	lanes := make([]lane, 0, len(m.Sections))
+	for _, sec := range m.Sections {
+		l := lane{name: sec.Label(), level: sec.Level()}
+		for _, task := range sec.Tasks {
+			l.rows = append(l.rows, []parser.Task{task})
+		}
+		lanes = append(lanes, l)
+	}
+	return lanes
+}
+
+// resourceLanes 按资源分组：每个资源一条泳道，多资源任务在各自泳道中重复出现；
+// 泳道内按开始时间排序，时间不重叠的任务压缩到同一行。
+func resourceLanes(m parser.Model) []lane {
+	var names []string
+	tasksByKey := make(map[string][]parser.Task)
+	add := func(name string, task parser.Task) {
+		key := strings.ToLower(name)
+		if _, ok := tasksByKey[key]; !ok {
+			names = append(names, name)
+		}
+		tasksByKey[key] = append(tasksByKey[key], task)
+	}
+	// 先按声明顺序建立泳道，未分配任务的泳道放在最后
+	for _, r := range m.Resources {
+		tasksByKey[strings.ToLower(r.Name)] = nil
+		names = append(names, r.Name)
+	}
+	var unassigned []parser.Task
+	for _, sec := range m.Sections {
+		for _, task := range sec.Tasks {
+			if task.IsVertical {
+				continue
+			}
+			if len(task.Resources) == 0 {
+				unassigned = append(unassigned, task)
+				continue
+			}
+			for _, r := range task.Resources {
+				add(r, task)
+			}
+		}
+	}
+
+	lanes := make([]lane, 0, len(names)+1)
+	for _, name := range names {
+		if tasks := tasksByKey[strings.ToLower(name)]; len(tasks) > 0 {
+			lanes = append(lanes, lane{name: name, rows: packRows(tasks)})
+		}
+	}
+	if len(unassigned) > 0 {
+		lanes = append(lanes, lane{name: unassignedLaneName, rows: packRows(unassigned)})
+	}
+	return lanes
+}
+
+// packRows 以首次适配方式将任务放入最早空出的行。
+func packRows(tasks []parser.Task) [][]parser.Task {
+	sorted := append([]parser.Task(nil), tasks...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+	var rows [][]parser.Task
+	var rowEnds []time.Time
+	for _, task := range sorted {
+		placed := false
+		for i := range rows {
+			if !task.Start.Before(rowEnds[i]) {
+				rows[i] = append(rows[i], task)
+				rowEnds[i] = occupiedUntil(task)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			rows = append(rows, []parser.Task{task})
+			rowEnds = append(rowEnds, occupiedUntil(task))
+		}
+	}
+	return rows
+}
+
+// occupiedUntil 返回任务在行内占用到的时刻：日级任务与里程碑占满结束日。
+func occupiedUntil(task parser.Task) time.Time {
+	if task.HasTime || task.Duration.Unit == parser.DurationMinute || task.Duration.Unit == parser.DurationHour {
+		return task.End
+	}
+	y, mth, d := task.End.Date()
+	return time.Date(y, mth, d+1, 0, 0, 0, 0, task.End.Location())
+}
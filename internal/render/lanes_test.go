@@ -0,0 +1,39 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/pyroflux/go-mermaid-gantt/internal/parser"
+)
+
+func TestResourceLanesCompactRows(t *testing.T) {
+	model, err := parser.Parse(`gantt
+dateFormat YYYY-MM-DD
+resource bob
+section API
+Build :a1, 2025-03-03, 3d, alice
+Review :r1, 2025-03-06, 1d, alice, bob
+section Web
+Screens :w1, 2025-03-04, 2d, alice
+Docs :d1, 2025-03-05, 1d
+`)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	model, err = parser.ResolveSchedule(model)
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	lanes := resourceLanes(model)
+	if len(lanes) != 3 || lanes[0].name != "bob" || lanes[1].name != "alice" || lanes[2].name != unassignedLaneName {
+		t.Fatalf("unexpected lanes: %+v", lanes)
+	}
+	// alice：a1 与 w1 重叠需两行，r1 在 a1 结束后复用第一行
+	alice := lanes[1].rows
+	if len(alice) != 2 || len(alice[0]) != 2 || alice[0][1].ID != "r1" || alice[1][0].ID != "w1" {
+		t.Fatalf("unexpected alice rows: %+v", alice)
+	}
+	if len(lanes[0].rows) != 1 || lanes[0].rows[0][0].ID != "r1" {
+		t.Fatalf("multi-resource task should appear in bob's lane: %+v", lanes[0].rows)
+	}
+}
@@ -75,6 +75,14 @@ const (
 	weekTickMinutes            = 7 * 24 * 60
 	monthTickMinutes           = 30 * 24 * 60
 	weekendDarkenFactor        = 0.9
+	linkInsetPx                = 6
+	linkThicknessPx            = 2
+	forecastBandAlpha          = 0x50
+	baselineGapPx              = 2
+	baselineThicknessPx        = 4
+	hatchSpacingPx             = 6
+	summaryTipPx               = 5
+	sectionIndentPx            = 16
 )
 
 const (
@@ -93,6 +101,13 @@ type Options struct {
 	FontPath string
 	Calendar parser.Calendar
 	Today    parser.TodayMarker
+
+	HighlightCritical bool // 关键路径任务使用 crit 配色，并绘制关键依赖连线
+	ShowSlack         bool // 在任务条后绘制总时差细线
+	GroupByResource   bool // 资源泳道视图：按资源而非 section 分组
+	SectionSummary    bool // 在 section 标题行绘制覆盖其任务起止的汇总条与汇总进度
+
+	EarnedValue *parser.EarnedValueReport // 非 nil 时在图表下方绘制挣值汇总块
 }
 
 // ThemeColors 绘制时用到的颜色。
@@ -107,6 +122,9 @@ type ThemeColors struct {
 	Milestone  color.Color
 	TodayLine  color.Color
 	Vertical   color.Color
+	Deadline   color.Color
+	Baseline   color.Color // 基线幽灵条
+	Slipped    color.Color // 完成晚于基线的任务
 }
 
 // RenderModel 绘制解析后的模型为 PNG 字节。
@@ -157,10 +175,16 @@ func RenderModel(_ context.Context, m parser.Model, opt Options) ([]byte, error)
 
 	timeMode := hasTimeGranularity(m)
 
+	// 行布局：默认按 section 每个任务一行，资源泳道视图按资源分组并压缩不重叠的任务
+	lanes := sectionLanes(m)
+	if opt.GroupByResource {
+		lanes = resourceLanes(m)
+	}
+
 	// 是否存在命名的 section
 	hasSectionHeader := false
-	for _, sec := range m.Sections {
-		if strings.TrimSpace(sec.Name) != "" {
+	for _, lane := range lanes {
+		if strings.TrimSpace(lane.name) != "" {
 			hasSectionHeader = true
 			break
 		}
@@ -209,6 +233,7 @@ func RenderModel(_ context.Context, m parser.Model, opt Options) ([]byte, error)
 
 	tickMinutes := tickToMinutes(m.Tick)
 	tickDays := tickToDays(m.Tick)
+	tickMonths := tickToMonths(m.Tick)
 	if !m.Tick.Valid {
 		autoMin, autoDay := autoTickInterval(minSpan, maxSpan, timeMode)
 		if tickMinutes == 0 {
@@ -217,6 +242,10 @@ func RenderModel(_ context.Context, m parser.Model, opt Options) ([]byte, error)
 		if tickDays == 0 {
 			tickDays = autoDay
 		}
+		// 自动月刻度按自然月对齐到每月 1 日
+		if (timeMode && autoMin == monthTickMinutes) || (!timeMode && autoDay == dayTickThirty) {
+			tickMonths = 1
+		}
 	}
 
 	scaledMinGridWidth := int(float64(minGridWidthPx) * scale)
@@ -281,8 +310,8 @@ func RenderModel(_ context.Context, m parser.Model, opt Options) ([]byte, error)
 		if hasSectionHeader {
 			contentHeight += rowHeight / halfDivisor
 		}
-		for _, sec := range m.Sections {
-			contentHeight += len(sec.Tasks) * rowHeight
+		for _, lane := range lanes {
+			contentHeight += len(lane.rows) * rowHeight
 			contentHeight += secGap
 		}
 		contentHeight += bottomMargin
@@ -290,8 +319,9 @@ func RenderModel(_ context.Context, m parser.Model, opt Options) ([]byte, error)
 	}
 	
 	// 画布尺寸不应受Scale影响，Scale应仅影响元素大小和位置
+	summary := earnedValueLines(opt.EarnedValue)
 	w := width
-	h := height
+	h := height + summaryHeight(summary, scale)
 
 	img := image.NewRGBA(image.Rect(0, 0, w, h))
 	draw.Draw(img, img.Bounds(), &image.Uniform{opt.Theme.Background}, image.Point{}, draw.Src)
@@ -304,23 +334,22 @@ func RenderModel(_ context.Context, m parser.Model, opt Options) ([]byte, error)
 	// 预计算 section 布局：紧贴轴线（取轴中线作为起点）
 	startY := topMargin + axisHeight/halfDivisor
 	type secInfo struct {
-		section parser.Section
-		start   int
-		end     int
+		start int
+		end   int
 	}
-	infos := make([]secInfo, 0, len(m.Sections))
+	infos := make([]secInfo, 0, len(lanes))
 	y := startY
-	for _, sec := range m.Sections {
+	for _, lane := range lanes {
 		secStart := y
 		if hasSectionHeader {
 			y += rowHeight / halfDivisor
 		}
-		y += len(sec.Tasks) * rowHeight
+		y += len(lane.rows) * rowHeight
 		secEnd := y
 		if hasSectionHeader {
 			y += secGap
 		}
-		infos = append(infos, secInfo{section: sec, start: secStart, end: secEnd})
+		infos = append(infos, secInfo{start: secStart, end: secEnd})
 	}
 
 	// 画 section 背景
@@ -331,8 +360,8 @@ func RenderModel(_ context.Context, m parser.Model, opt Options) ([]byte, error)
 		secColor := sectionBgColor(opt.Theme.Background, idx)
 		fillRect(img, image.Rect(0, info.start, w, info.end+secGap), secColor)
 	}
-	// 统一时间轴填充高度：从刻度线开始直至画布底部，确保背景覆盖完整内容区域和底部留白。
-	timelineEnd := h
+	// 统一时间轴填充高度：从刻度线开始直至画布底部（挣值汇总块之上），确保背景覆盖完整内容区域和底部留白。
+	timelineEnd := height
 
 	// 当前日期位置（按当天百分比放置，超出范围则夹紧到起/止边界）
 	todayTime := today.Date
@@ -374,7 +403,7 @@ func RenderModel(_ context.Context, m parser.Model, opt Options) ([]byte, error)
 	}
 
 	if timeMode {
-		drawTimelineMinutes(img, leftMargin, topMargin, gridWidth, axisHeight, minSpan, maxSpan, m.AxisFormat, opt.Theme, calendar, timelineEnd, tickMinutes, weekendFill, opt.FontPath, scale)
+		drawTimelineMinutes(img, leftMargin, topMargin, gridWidth, axisHeight, minSpan, maxSpan, m.AxisFormat, opt.Theme, calendar, timelineEnd, tickMinutes, tickMonths, weekendFill, opt.FontPath, scale)
 	} else {
 		totalDays := calendarSpanDays(minStart, maxEnd)
 		if totalDays <= 0 {
@@ -389,7 +418,8 @@ func RenderModel(_ context.Context, m parser.Model, opt Options) ([]byte, error)
 		} else {
 			weekStart = nil
 		}
-		drawTimeline(img, leftMargin, topMargin, totalDays, axisHeight, dayWidth, minStart, m.AxisFormat, opt.Theme, calendar, timelineEnd, hasToday, todayX, tickDays, weekStart, weekendFill, opt.FontPath, scale)
+		drawTimeline(img, leftMargin, topMargin, totalDays, axisHeight, dayWidth, minStart, m.AxisFormat, opt.Theme, calendar, timelineEnd, hasToday, todayX, tickDays, tickMonths, weekStart, weekendFill, opt.FontPath, scale)
+		drawHolidayLabels(img, leftMargin, timelineEnd, totalDays, dayWidth, minStart, calendar, opt.Theme.Text, opt.FontPath, scale)
 	}
 
 	// 垂直标记（不占用行）
@@ -399,101 +429,206 @@ func RenderModel(_ context.Context, m parser.Model, opt Options) ([]byte, error)
 		drawVerticalMarkers(img, leftMargin, topMargin, timelineEnd, minStart, maxEnd, gridWidth, dayWidth, timeMode, opt.Theme, m.Verticals)
 	}
 
-	// 绘制 section 标题与任务
-	y = startY
-	for _, sec := range m.Sections {
-		if hasSectionHeader {
-			drawBoldText(img, opt.Theme.Emphasis, leftMargin/halfDivisor, y+rowHeight/halfDivisor, sec.Name, opt.FontPath, int(float64(sectionFontSize)*scale))
-			y += rowHeight / halfDivisor
+	// dateX 将时间点映射到横坐标：分钟模式按比例，日模式按日历日对齐
+	dateX := func(t time.Time) int {
+		if timeMode {
+			totalMinutes := maxSpan.Sub(minSpan).Minutes()
+			if totalMinutes <= 0 {
+				totalMinutes = 1
+			}
+			return leftMargin + int(float64(gridWidth)*(t.Sub(minSpan).Minutes()/totalMinutes))
 		}
-		for _, task := range sec.Tasks {
-			x := leftMargin
-			var widthPx int
-			if timeMode {
-				totalMinutes := maxSpan.Sub(minSpan).Minutes()
-				if totalMinutes <= 0 {
-					totalMinutes = 1
-				}
-				offsetMinutes := task.Start.Sub(minSpan).Minutes()
-				if offsetMinutes < 0 {
-					offsetMinutes = 0
-				}
-				x = leftMargin + int(float64(gridWidth)*(offsetMinutes/totalMinutes))
-				durationMinutes := task.End.Sub(task.Start).Minutes()
-				if durationMinutes <= 0 {
-					durationMinutes = 1
-				}
-				widthPx = int(float64(gridWidth) * (durationMinutes / totalMinutes))
-				if widthPx < minTaskWidthPx {
-					widthPx = minTaskWidthPx
-				}
-			} else {
-				if !task.Start.IsZero() {
-					offset := calendarOffset(minStart, task.Start)
-					if offset < 0 {
-						offset = 0
-					}
-					x = leftMargin + offset*dayWidth
-				}
-				duration := task.DurationDays
-				if duration <= 0 {
-					duration = 1
-				}
-				widthPx = duration * dayWidth
-				if widthPx < dayWidth {
-					widthPx = dayWidth
+		return leftMargin + calendarOffset(minStart, t)*dayWidth
+	}
+
+	// barSpan 计算任务条的横坐标与宽度：分钟模式按比例，日模式按天数
+	barSpan := func(start, end time.Time, days int) (int, int) {
+		x := leftMargin
+		var widthPx int
+		if timeMode {
+			totalMinutes := maxSpan.Sub(minSpan).Minutes()
+			if totalMinutes <= 0 {
+				totalMinutes = 1
+			}
+			offsetMinutes := start.Sub(minSpan).Minutes()
+			if offsetMinutes < 0 {
+				offsetMinutes = 0
+			}
+			x = leftMargin + int(float64(gridWidth)*(offsetMinutes/totalMinutes))
+			durationMinutes := end.Sub(start).Minutes()
+			if durationMinutes <= 0 {
+				durationMinutes = 1
+			}
+			widthPx = int(float64(gridWidth) * (durationMinutes / totalMinutes))
+			if widthPx < minTaskWidthPx {
+				widthPx = minTaskWidthPx
+			}
+		} else {
+			if !start.IsZero() {
+				offset := calendarOffset(minStart, start)
+				if offset < 0 {
+					offset = 0
 				}
+				x = leftMargin + offset*dayWidth
 			}
+			duration := days
+			if duration <= 0 {
+				duration = 1
+			}
+			widthPx = duration * dayWidth
+			if widthPx < dayWidth {
+				widthPx = dayWidth
+			}
+		}
+		return x, widthPx
+	}
 
-			barTop := y + (rowHeight-barHeight)/halfDivisor
-			if task.IsMilestone || task.Duration.Value == 0 {
-				markerWidth := widthPx
-				if markerWidth < barHeight {
-					markerWidth = barHeight
+	// 绘制 section 标题与任务
+	bars := make(map[string]image.Rectangle)
+	// drawTask 在 y 所在行绘制单个任务
+	drawTask := func(task parser.Task, y int) {
+		x, widthPx := barSpan(task.Start, task.End, task.DurationDays)
+
+		barTop := y + (rowHeight-barHeight)/halfDivisor
+		bars[task.ID] = image.Rect(x, barTop, x+widthPx, barTop+barHeight)
+		if opt.ShowSlack && task.TotalSlack > 0 && len(task.Occurrences) == 0 {
+			lateEnd := task.LateFinish
+			if !timeMode {
+				lateEnd = time.Date(lateEnd.Year(), lateEnd.Month(), lateEnd.Day()+1, 0, 0, 0, 0, lateEnd.Location())
+			}
+			drawSlackWhisker(img, opt.Theme.TaskBorder, x+widthPx, dateX(lateEnd), barTop+barHeight/halfDivisor, barHeight, scale)
+		}
+		if deadline, ok := task.Deadline(); ok {
+			dy, dm, dd := deadline.Date()
+			drawDeadlineMarker(img, opt.Theme.Deadline, dateX(time.Date(dy, dm, dd+1, 0, 0, 0, 0, minStart.Location())), barTop, barHeight)
+		}
+		if !task.BaselineStart.IsZero() {
+			days := 0
+			if !task.IsMilestone && task.Duration.Value != 0 {
+				days = calendarSpanDays(task.BaselineStart, task.BaselineEnd)
+			}
+			bx, bw := barSpan(task.BaselineStart, task.BaselineEnd, days)
+			drawBaselineBar(img, opt.Theme.Baseline, bx, bw, barTop+barHeight, (rowHeight-barHeight)/halfDivisor, scale)
+		}
+		if len(task.Occurrences) > 0 {
+			drawOccurrences(img, task, barSpan, barTop, barHeight, opt, scale)
+			return
+		}
+		if task.IsMilestone || task.Duration.Value == 0 {
+			markerWidth := widthPx
+			if markerWidth < barHeight {
+				markerWidth = barHeight
+			}
+			if !task.ForecastLow.IsZero() {
+				high := task.ForecastHigh
+				if !timeMode {
+					high = time.Date(high.Year(), high.Month(), high.Day()+1, 0, 0, 0, 0, high.Location())
 				}
-				drawMilestone(img, opt.Theme.Milestone, x, barTop, markerWidth, barHeight)
-				drawText(img, opt.Theme.Text, x+markerWidth/halfDivisor, barTop-barHeight/halfDivisor, task.Name, opt.FontPath, int(float64(taskFontSize)*scale))
-				y += rowHeight
-				continue
+				drawForecastBand(img, opt.Theme.Milestone, dateX(task.ForecastLow), dateX(high), barTop, barHeight)
 			}
+			drawMilestone(img, opt.Theme.Milestone, x, barTop, markerWidth, barHeight)
+			drawText(img, opt.Theme.Text, x+markerWidth/halfDivisor, barTop-barHeight/halfDivisor, task.Name, opt.FontPath, int(float64(taskFontSize)*scale))
+			return
+		}
 
-			fill, border := statusColors(opt.Theme, task.Status)
-			rect := image.Rect(x, barTop, x+widthPx, barTop+barHeight)
-			draw.Draw(img, rect, &image.Uniform{fill}, image.Point{}, draw.Src)
-			drawBorder(img, rect, border)
-
-			if task.Progress > 0 {
-				progressWidth := int(float64(rect.Dx()) * float64(task.Progress) / progressDivisor)
-				if progressWidth > 0 {
-					progRect := image.Rect(rect.Min.X, rect.Min.Y, rect.Min.X+progressWidth, rect.Max.Y)
-					draw.Draw(img, progRect, &image.Uniform{opt.Theme.Milestone}, image.Point{}, draw.Over)
-				}
+		status := task.Status
+		if opt.HighlightCritical && task.Critical {
+			status = parser.StatusCritical
+		}
+		fill, border := statusColors(opt.Theme, status)
+		if task.Slipped {
+			fill = opt.Theme.Slipped
+		}
+		if task.DeadlineMissed {
+			fill = opt.Theme.Deadline
+		}
+		rect := image.Rect(x, barTop, x+widthPx, barTop+barHeight)
+		draw.Draw(img, rect, &image.Uniform{fill}, image.Point{}, draw.Src)
+		if task.Reforecast {
+			plannedEnd := task.PlannedEnd
+			if !timeMode {
+				plannedEnd = time.Date(plannedEnd.Year(), plannedEnd.Month(), plannedEnd.Day()+1, 0, 0, 0, 0, plannedEnd.Location())
 			}
+			drawForecastExtension(img, opt.Theme.Slipped, rect, dateX(plannedEnd), scale)
+		}
+		drawBorder(img, rect, border)
 
-			// 文本：优先放条内，空间不足则放在条右侧
-			padding := int(float64(labelPaddingPx) * scale)
-			labelMeasured := measureTextWidth(task.Name, opt.Theme.TaskText, opt.FontPath, int(float64(taskFontSize)*scale))
-			innerRoom := rect.Dx() - padding*doubleMultiplier
-			labelX := rect.Min.X + rect.Dx()/halfDivisor
-			labelY := rect.Min.Y + rect.Dy()/halfDivisor
-			labelColor := opt.Theme.TaskText
-			if labelMeasured > innerRoom {
-				labelX = rect.Max.X + padding + labelMeasured/halfDivisor
-				labelColor = opt.Theme.TaskFill // 写在外侧时用任务背景色，避免与背景重叠难读
+		if task.Progress > 0 {
+			progressWidth := int(float64(rect.Dx()) * float64(task.Progress) / progressDivisor)
+			if progressWidth > 0 {
+				progRect := image.Rect(rect.Min.X, rect.Min.Y, rect.Min.X+progressWidth, rect.Max.Y)
+				draw.Draw(img, progRect, &image.Uniform{opt.Theme.Milestone}, image.Point{}, draw.Over)
 			}
-			var label string
-			if labelMeasured > innerRoom {
-				label = task.Name
+		}
+
+		// 文本：优先放条内，空间不足则放在条右侧
+		padding := int(float64(labelPaddingPx) * scale)
+		labelMeasured := measureTextWidth(task.Name, opt.Theme.TaskText, opt.FontPath, int(float64(taskFontSize)*scale))
+		innerRoom := rect.Dx() - padding*doubleMultiplier
+		labelX := rect.Min.X + rect.Dx()/halfDivisor
+		labelY := rect.Min.Y + rect.Dy()/halfDivisor
+		labelColor := opt.Theme.TaskText
+		if labelMeasured > innerRoom {
+			labelX = rect.Max.X + padding + labelMeasured/halfDivisor
+			labelColor = opt.Theme.TaskFill // 写在外侧时用任务背景色，避免与背景重叠难读
+		}
+		var label string
+		if labelMeasured > innerRoom {
+			label = task.Name
+		} else {
+			label = fitText(img, task.Name, innerRoom, opt.Theme.TaskText, opt.FontPath, int(float64(taskFontSize)*scale))
+		}
+		drawText(img, labelColor, labelX, labelY, label, opt.FontPath, int(float64(taskFontSize)*scale))
+	}
+
+	var rollups []parser.SectionRollup
+	if opt.SectionSummary && !opt.GroupByResource {
+		rollups = parser.RollupSections(m)
+	}
+	y = startY
+	for idx, lane := range lanes {
+		if hasSectionHeader {
+			if lane.level == 0 {
+				drawBoldText(img, opt.Theme.Emphasis, leftMargin/halfDivisor, y+rowHeight/halfDivisor, lane.name, opt.FontPath, int(float64(sectionFontSize)*scale))
 			} else {
-				label = fitText(img, task.Name, innerRoom, opt.Theme.TaskText, opt.FontPath, int(float64(taskFontSize)*scale))
+				// 下级分组按深度缩进，使用常规字重区分层级
+				indent := int(float64(lane.level*sectionIndentPx) * scale)
+				drawText(img, opt.Theme.Emphasis, leftMargin/halfDivisor+indent, y+rowHeight/halfDivisor, lane.name, opt.FontPath, int(float64(sectionFontSize)*scale))
+			}
+			if idx < len(rollups) && rollups[idx].Tasks > 0 {
+				// 汇总条覆盖本 section 及其下级分组各任务条的并集，与任务条的取整方式保持一致
+				left, right := -1, -1
+				for _, si := range parser.SectionSubtree(m.Sections, idx) {
+					for _, task := range m.Sections[si].Tasks {
+						x, widthPx := barSpan(task.Start, task.End, task.DurationDays)
+						if left < 0 || x < left {
+							left = x
+						}
+						if x+widthPx > right {
+							right = x + widthPx
+						}
+					}
+				}
+				drawSummaryBar(img, opt.Theme.TaskBorder, opt.Theme.Milestone, left, right-left, y+rowHeight/(halfDivisor*halfDivisor), barHeight, rollups[idx].Progress, scale)
+			}
+			y += rowHeight / halfDivisor
+		}
+		for _, row := range lane.rows {
+			for _, task := range row {
+				drawTask(task, y)
 			}
-			drawText(img, labelColor, labelX, labelY, label, opt.FontPath, int(float64(taskFontSize)*scale))
 			y += rowHeight
 		}
 		if hasSectionHeader {
 			y += secGap // section 间隔
 		}
 	}
+	if opt.HighlightCritical {
+		drawCriticalLinks(img, m, bars, opt.Theme.TodayLine, scale)
+	}
+	if len(summary) > 0 {
+		drawSummary(img, summary, leftMargin, height, opt.Theme, opt.EarnedValue.Project.SPI < 1 && opt.EarnedValue.Project.PV > 0, opt.FontPath, scale)
+	}
 
 	buf := bytes.NewBuffer(nil)
 	if err := pngEncode(buf, img); err != nil {
@@ -555,6 +690,15 @@ func timelineBounds(m parser.Model) (min time.Time, max time.Time) {
 			if end.After(max) {
 				max = end
 			}
+			if task.ForecastHigh.After(max) {
+				max = task.ForecastHigh
+			}
+			if !task.BaselineStart.IsZero() && task.BaselineStart.Before(min) {
+				min = task.BaselineStart
+			}
+			if task.BaselineEnd.After(max) {
+				max = task.BaselineEnd
+			}
 		}
 	}
 	for _, v := range m.Verticals {
@@ -614,7 +758,7 @@ func autoTickInterval(min, max time.Time, timeMode bool) (tickMinutes int, tickD
 		case days <= daysThresholdYear:
 			return weekTickMinutes, 0 // 周刻度
 		default:
-			return monthTickMinutes, 0 // 月刻度（按 30 天）
+			return monthTickMinutes, 0 // 月刻度（绘制时对齐到月初）
 		}
 	}
 
@@ -624,7 +768,7 @@ func autoTickInterval(min, max time.Time, timeMode bool) (tickMinutes int, tickD
 	case days <= daysThresholdYear:
 		return 0, dayTickSeven // 周
 	default:
-		return 0, dayTickThirty // 月
+		return 0, dayTickThirty // 月（绘制时对齐到月初）
 	}
 }
 
@@ -770,34 +914,209 @@ func drawMilestone(img *image.RGBA, c color.Color, x, y, dayWidth, barHeight int
 	}
 }
 
-func isExcludedDay(t time.Time, cal parser.Calendar) bool {
-	if cal.ExcludeWeekend && isWeekendDay(t, cal) {
-		for _, d := range cal.IncludeDates {
-			if sameDay(t, d) {
-				return false
+// drawOccurrences 将重复任务的全部实例画在同一行，标签放在最后一个实例右侧。
+func drawOccurrences(img *image.RGBA, task parser.Task, barSpan func(time.Time, time.Time, int) (int, int), barTop, barHeight int, opt Options, scale float64) {
+	milestone := task.IsMilestone || task.Duration.Value == 0
+	fill, border := statusColors(opt.Theme, task.Status)
+	if task.DeadlineMissed {
+		fill = opt.Theme.Deadline
+	}
+	lastRight := 0
+	for _, occ := range task.Occurrences {
+		days := 0
+		if !milestone {
+			days = calendarSpanDays(occ.Start, occ.End)
+		}
+		x, widthPx := barSpan(occ.Start, occ.End, days)
+		if milestone {
+			markerWidth := widthPx
+			if markerWidth < barHeight {
+				markerWidth = barHeight
 			}
+			drawMilestone(img, opt.Theme.Milestone, x, barTop, markerWidth, barHeight)
+			lastRight = x + markerWidth
+			continue
 		}
-		return true
+		rect := image.Rect(x, barTop, x+widthPx, barTop+barHeight)
+		draw.Draw(img, rect, &image.Uniform{fill}, image.Point{}, draw.Src)
+		drawBorder(img, rect, border)
+		lastRight = rect.Max.X
+	}
+	padding := int(float64(labelPaddingPx) * scale)
+	size := int(float64(taskFontSize) * scale)
+	labelWidth := measureTextWidth(task.Name, opt.Theme.Text, opt.FontPath, size)
+	labelColor := opt.Theme.TaskFill
+	if milestone {
+		labelColor = opt.Theme.Text
+	}
+	drawText(img, labelColor, lastRight+padding+labelWidth/halfDivisor, barTop+barHeight/halfDivisor, task.Name, opt.FontPath, size)
+}
+
+// drawSlackWhisker 从条尾到最迟完成位置绘制细线，末端带竖向短杠。
+func drawSlackWhisker(img *image.RGBA, c color.Color, fromX, toX, midY, barHeight int, scale float64) {
+	if toX <= fromX {
+		return
 	}
-	for _, d := range cal.ExcludeDates {
-		if sameDay(t, d) {
-			return true
+	thickness := int(scale)
+	if thickness < 1 {
+		thickness = 1
+	}
+	fillRect(img, image.Rect(fromX, midY, toX, midY+thickness), c)
+	tick := barHeight / thirdDivisor
+	fillRect(img, image.Rect(toX-thickness, midY-tick, toX, midY+tick+thickness), c)
+}
+
+// drawBaselineBar 在任务条下方的行间空隙绘制基线细条，room 为条底到行底的高度。
+func drawBaselineBar(img *image.RGBA, c color.Color, x, width, barBottom, room int, scale float64) {
+	gap := int(float64(baselineGapPx) * scale)
+	thickness := int(float64(baselineThicknessPx) * scale)
+	if thickness > room-gap {
+		thickness = room - gap
+	}
+	if thickness < 1 {
+		thickness = 1
+	}
+	fillRect(img, image.Rect(x, barBottom+gap, x+width, barBottom+gap+thickness), c)
+}
+
+// drawSummaryBar 以细条加两端下折的括弧绘制 section 汇总条，midY 为条的中线，进度按宽度比例覆盖。
+func drawSummaryBar(img *image.RGBA, c, progressColor color.Color, x, width, midY, barHeight, progress int, scale float64) {
+	thickness := barHeight / thirdDivisor
+	if thickness < 1 {
+		thickness = 1
+	}
+	top := midY - thickness/halfDivisor
+	fillRect(img, image.Rect(x, top, x+width, top+thickness), c)
+	if progress > 0 {
+		done := int(float64(width) * float64(progress) / progressDivisor)
+		fillRect(img, image.Rect(x, top, x+done, top+thickness), progressColor)
+	}
+	tip := int(float64(summaryTipPx) * scale)
+	for i := 0; i < tip; i++ {
+		fillRect(img, image.Rect(x, top+thickness+i, x+tip-i, top+thickness+i+1), c)
+		fillRect(img, image.Rect(x+width-tip+i, top+thickness+i, x+width, top+thickness+i+1), c)
+	}
+}
+
+// drawForecastExtension 以斜线填充任务条中超出原计划完成（plannedX 之后）的部分，并在原计划完成处画竖线。
+func drawForecastExtension(img *image.RGBA, c color.Color, rect image.Rectangle, plannedX int, scale float64) {
+	from := plannedX
+	if from < rect.Min.X {
+		from = rect.Min.X
+	}
+	if from >= rect.Max.X {
+		return
+	}
+	spacing := int(float64(hatchSpacingPx) * scale)
+	if spacing < halfDivisor {
+		spacing = halfDivisor
+	}
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := from; x < rect.Max.X; x++ {
+			if (x+y)%spacing < spacing/halfDivisor {
+				img.Set(x, y, c)
+			}
 		}
 	}
-	return false
+	if plannedX > rect.Min.X {
+		fillRect(img, image.Rect(plannedX-1, rect.Min.Y, plannedX+1, rect.Max.Y), c)
+	}
+}
+
+// drawForecastBand 在里程碑所在行绘制半透明的预测区间（P50–P90）。
+func drawForecastBand(img *image.RGBA, c color.Color, fromX, toX, barTop, barHeight int) {
+	if toX <= fromX {
+		return
+	}
+	rgba := color.RGBAModel.Convert(c).(color.RGBA)
+	band := color.NRGBA{R: rgba.R, G: rgba.G, B: rgba.B, A: forecastBandAlpha}
+	draw.Draw(img, image.Rect(fromX, barTop, toX, barTop+barHeight), &image.Uniform{band}, image.Point{}, draw.Over)
 }
 
-func isWeekendDay(t time.Time, cal parser.Calendar) bool {
-	weekends := cal.WeekendDays
-	if len(weekends) == 0 {
-		weekends = []time.Weekday{time.Saturday, time.Sunday}
+// drawCriticalLinks 为关键依赖绘制折线箭头：从前驱的结束（或开始）水平引出，竖直落到后继条上。
+func drawCriticalLinks(img *image.RGBA, m parser.Model, bars map[string]image.Rectangle, c color.Color, scale float64) {
+	inset := int(float64(linkInsetPx) * scale)
+	thickness := int(float64(linkThicknessPx) * scale)
+	if thickness < 1 {
+		thickness = 1
 	}
-	for _, wd := range weekends {
-		if t.Weekday() == wd {
-			return true
+	for _, sec := range m.Sections {
+		for _, task := range sec.Tasks {
+			for _, dep := range task.Dependencies {
+				if !dep.Critical {
+					continue
+				}
+				from, to := dep.Target, task.ID
+				if dep.Type == parser.DepBefore {
+					from, to = task.ID, dep.Target
+				}
+				src, ok1 := bars[from]
+				dst, ok2 := bars[to]
+				if !ok1 || !ok2 {
+					continue
+				}
+				sx := src.Max.X
+				if dep.Type == parser.DepStartStart || dep.Type == parser.DepStartFinish {
+					sx = src.Min.X
+				}
+				// 落点略缩进条内，避免压在边角
+				tx := dst.Min.X + minInt(inset, dst.Dx()/halfDivisor)
+				if dep.Type == parser.DepFinishFinish || dep.Type == parser.DepStartFinish {
+					tx = dst.Max.X - minInt(inset, dst.Dx()/halfDivisor)
+				}
+				sy := src.Min.Y + src.Dy()/halfDivisor
+				ty := dst.Min.Y
+				if dst.Min.Y < src.Min.Y {
+					ty = dst.Max.Y
+				}
+				drawLinkPath(img, c, sx, sy, tx, ty, thickness, inset)
+			}
+		}
+	}
+}
+
+func drawLinkPath(img *image.RGBA, c color.Color, sx, sy, tx, ty, thickness, head int) {
+	x0, x1 := sx, tx
+	if x0 > x1 {
+		x0, x1 = x1, x0
+	}
+	fillRect(img, image.Rect(x0, sy, x1+thickness, sy+thickness), c)
+	y0, y1 := sy, ty
+	dir := 1
+	if y0 > y1 {
+		y0, y1 = y1, y0
+		dir = -1
+	}
+	fillRect(img, image.Rect(tx, y0, tx+thickness, y1), c)
+	// 箭头朝向后继条
+	for i := 0; i < head; i++ {
+		yy := ty - dir*i
+		fillRect(img, image.Rect(tx-i, yy, tx+thickness+i, yy+1), c)
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// drawDeadlineMarker 在任务行的截止位置绘制竖线与顶部倒三角。
+func drawDeadlineMarker(img *image.RGBA, c color.Color, x, barTop, barHeight int) {
+	overhang := barHeight / thirdDivisor
+	for yy := barTop - overhang; yy < barTop+barHeight+overhang; yy++ {
+		img.Set(x, yy, c)
+		img.Set(x-1, yy, c)
+	}
+	half := barHeight / halfDivisor / halfDivisor
+	top := barTop - overhang - half
+	for dy := 0; dy <= half; dy++ {
+		span := half - dy
+		for dx := -span; dx <= span; dx++ {
+			img.Set(x+dx, top+dy, c)
 		}
 	}
-	return false
 }
 
 func abs(v int) int {
@@ -807,7 +1126,7 @@ func abs(v int) int {
 	return v
 }
 
-func drawTimelineMinutes(img *image.RGBA, xStart, yStart, width, axisHeight int, minStart, maxEnd time.Time, axisFormat string, theme ThemeColors, calendar parser.Calendar, endY int, forcedTickMinutes int, weekendFill color.Color, fontPath string, scale float64) {
+func drawTimelineMinutes(img *image.RGBA, xStart, yStart, width, axisHeight int, minStart, maxEnd time.Time, axisFormat string, theme ThemeColors, calendar parser.Calendar, endY int, forcedTickMinutes, tickMonths int, weekendFill color.Color, fontPath string, scale float64) {
 	totalMinutes := int(maxEnd.Sub(minStart).Minutes())
 	if totalMinutes <= 0 {
 		totalMinutes = 1
@@ -837,13 +1156,26 @@ func drawTimelineMinutes(img *image.RGBA, xStart, yStart, width, axisHeight int,
 	}
 	for d := 0; d*dayMinutes <= totalMinutes; d++ {
 		dayStart := minStart.AddDate(0, 0, d)
-		if isExcludedDay(dayStart, calendar) {
+		if !calendar.IsWorkingDay(dayStart) {
 			dx := xStart + int(float64(d*dayMinutes)*pixelsPerMinute)
 			fillRect(img, image.Rect(dx, yStart, dx+dayPixels, endY), weekendFill)
 		}
 	}
-	// 垂直网格线
-	for i := tickOffset; i <= totalMinutes; i += tickMinutes {
+	if calendar.WorkHours.Enabled() {
+		drawOffHours(img, xStart, yStart, endY, minStart, maxEnd, pixelsPerMinute, calendar, weekendFill)
+	}
+	// 垂直网格线：月刻度落在每月 1 日，其余按固定分钟步进
+	var months []time.Time
+	if tickMonths > 0 {
+		months = monthTicks(minStart, maxEnd, tickMonths)
+		for _, tick := range months {
+			x := xStart + int(tick.Sub(minStart).Minutes()*pixelsPerMinute)
+			for yy := yStart; yy < endY; yy++ {
+				img.Set(x, yy, theme.Grid)
+			}
+		}
+	}
+	for i := tickOffset; tickMonths == 0 && i <= totalMinutes; i += tickMinutes {
 		x := xStart + int(float64(i)*pixelsPerMinute)
 		for yy := yStart; yy < endY; yy++ {
 			img.Set(x, yy, theme.Grid)
@@ -870,8 +1202,13 @@ func drawTimelineMinutes(img *image.RGBA, xStart, yStart, width, axisHeight int,
 	}
 	face, _, _ := font.LoadFaceWithFallback(float64(adjustedFontSize), fontPath)
 	scaledTickOffset := int(float64(tickLabelOffsetPx) * scale)
+	for _, tick := range months {
+		x := xStart + int(tick.Sub(minStart).Minutes()*pixelsPerMinute)
+		labelY := yStart + axisHeight/halfDivisor - scaledTickOffset
+		drawTextWithFace(img, theme.Text, x+scaledTickOffset, labelY, face, tick.Format(format))
+	}
 	step := labelStep
-	for i := labelOffset; i <= totalMinutes; i += step {
+	for i := labelOffset; tickMonths == 0 && i <= totalMinutes; i += step {
 		x := xStart + int(float64(i)*pixelsPerMinute)
 		date := minStart.Add(time.Duration(i) * time.Minute).Format(format)
 		labelY := yStart + axisHeight/halfDivisor - scaledTickOffset
@@ -879,6 +1216,70 @@ func drawTimelineMinutes(img *image.RGBA, xStart, yStart, width, axisHeight int,
 	}
 }
 
+// drawOffHours 在分钟轴上为工作时段之外的时间着色（排除日已整体着色）。
+func drawOffHours(img *image.RGBA, xStart, yStart, endY int, minStart, maxEnd time.Time, pixelsPerMinute float64, calendar parser.Calendar, fill color.Color) {
+	shade := func(from, to time.Time) {
+		if from.Before(minStart) {
+			from = minStart
+		}
+		if to.After(maxEnd) {
+			to = maxEnd
+		}
+		if !from.Before(to) {
+			return
+		}
+		x0 := xStart + int(from.Sub(minStart).Minutes()*pixelsPerMinute)
+		x1 := xStart + int(to.Sub(minStart).Minutes()*pixelsPerMinute)
+		fillRect(img, image.Rect(x0, yStart, x1, endY), fill)
+	}
+	first := time.Date(minStart.Year(), minStart.Month(), minStart.Day(), 0, 0, 0, 0, minStart.Location())
+	for day := first; day.Before(maxEnd); day = day.AddDate(0, 0, 1) {
+		if !calendar.IsWorkingDay(day) {
+			continue
+		}
+		prev := day
+		for _, r := range calendar.WorkHours.For(day.Weekday()) {
+			shade(prev, day.Add(time.Duration(r.Start)*time.Minute))
+			prev = day.Add(time.Duration(r.End) * time.Minute)
+		}
+		shade(prev, day.AddDate(0, 0, 1))
+	}
+}
+
+// drawHolidayLabels 在排除日列底部标注假日名称，连续同名的假日合并为一段，放不下时省略。
+func drawHolidayLabels(img *image.RGBA, xStart, endY, days, dayWidth int, minStart time.Time, calendar parser.Calendar, c color.Color, fontPath string, scale float64) {
+	if len(calendar.Holidays) == 0 {
+		return
+	}
+	size := int(float64(axisFontSize) * scale)
+	padding := int(float64(labelPaddingPx) * scale)
+	nameAt := func(day time.Time) string {
+		if calendar.IsWorkingDay(day) {
+			return ""
+		}
+		for _, h := range calendar.Holidays {
+			if !h.Workday && sameDay(h.Date, day) {
+				return h.Name
+			}
+		}
+		return ""
+	}
+	for i := 0; i < days; {
+		name := nameAt(minStart.AddDate(0, 0, i))
+		j := i + 1
+		for j < days && name != "" && nameAt(minStart.AddDate(0, 0, j)) == name {
+			j++
+		}
+		if name != "" {
+			blockWidth := (j - i) * dayWidth
+			if measureTextWidth(name, c, fontPath, size)+padding*doubleMultiplier <= blockWidth {
+				drawText(img, c, xStart+i*dayWidth+blockWidth/halfDivisor, endY-size, name, fontPath, size)
+			}
+		}
+		i = j
+	}
+}
+
 func drawVerticalMarkers(img *image.RGBA, xStart, yStart, endY int, spanStart, spanEnd time.Time, gridWidth, dayWidth int, timeMode bool, theme ThemeColors, verts []parser.Task) {
 	if len(verts) == 0 {
 		return
@@ -912,7 +1313,7 @@ func drawVerticalMarkers(img *image.RGBA, xStart, yStart, endY int, spanStart, s
 	}
 }
 
-func drawTimeline(img *image.RGBA, xStart, yStart, days, axisHeight, dayWidth int, minStart time.Time, axisFormat string, theme ThemeColors, calendar parser.Calendar, endY int, hasToday bool, todayX int, forcedTickDays int, weekStart *time.Weekday, weekendFill color.Color, fontPath string, scale float64) {
+func drawTimeline(img *image.RGBA, xStart, yStart, days, axisHeight, dayWidth int, minStart time.Time, axisFormat string, theme ThemeColors, calendar parser.Calendar, endY int, hasToday bool, todayX int, forcedTickDays, tickMonths int, weekStart *time.Weekday, weekendFill color.Color, fontPath string, scale float64) {
 	width := dayWidth * days
 	lineY := yStart + axisHeight/halfDivisor
 
@@ -941,7 +1342,7 @@ func drawTimeline(img *image.RGBA, xStart, yStart, days, axisHeight, dayWidth in
 	for i := 0; i < days; i++ {
 		x := xStart + i*dayWidth
 		dayDate := minStart.AddDate(0, 0, i)
-		if isExcludedDay(dayDate, calendar) {
+		if !calendar.IsWorkingDay(dayDate) {
 			fillRect(img, image.Rect(x, lineY, x+dayWidth, endY), weekendFill)
 		}
 	}
@@ -949,16 +1350,24 @@ func drawTimeline(img *image.RGBA, xStart, yStart, days, axisHeight, dayWidth in
 	for yy := yStart; yy < endY; yy++ {
 		img.Set(xStart, yy, theme.Grid)
 	}
-	// 垂直网格线：与刻度标签使用同一起点和步长
-	gridStartDay := minStart
-	if weekStart != nil {
-		gridStartDay = alignToWeekStart(minStart, weekStart)
+	// 垂直网格线：与刻度标签使用同一组刻度；月刻度对齐到每月 1 日
+	var ticks []time.Time
+	if tickMonths > 0 {
+		ticks = monthTicks(minStart, minStart.AddDate(0, 0, days), tickMonths)
+		for step := tickMonths + 1; len(ticks) > maxDayTicks; step++ {
+			ticks = monthTicks(minStart, minStart.AddDate(0, 0, days), step)
+		}
+	} else {
+		gridStartDay := minStart
+		if weekStart != nil {
+			gridStartDay = alignToWeekStart(minStart, weekStart)
+		}
+		for cur := gridStartDay; calendarOffset(minStart, cur) <= days; cur = cur.AddDate(0, 0, tickEvery) {
+			ticks = append(ticks, cur)
+		}
 	}
-	for cur := gridStartDay; ; cur = cur.AddDate(0, 0, tickEvery) {
+	for _, cur := range ticks {
 		offsetDays := calendarOffset(minStart, cur)
-		if offsetDays > days {
-			break
-		}
 		if offsetDays > 0 { // > 0 避免重复画起点线
 			x := xStart + offsetDays*dayWidth
 			for yy := yStart; yy < endY; yy++ {
@@ -988,15 +1397,8 @@ func drawTimeline(img *image.RGBA, xStart, yStart, days, axisHeight, dayWidth in
 	if strings.TrimSpace(format) == "" {
 		format = "01-02"
 	}
-	startDay := minStart
-	if weekStart != nil {
-		startDay = alignToWeekStart(minStart, weekStart)
-	}
-	for cur := startDay; ; cur = cur.AddDate(0, 0, tickEvery) {
+	for _, cur := range ticks {
 		offsetDays := calendarOffset(minStart, cur)
-		if offsetDays > days {
-			break
-		}
 		if offsetDays >= 0 {
 			x := xStart + offsetDays*dayWidth
 			date := cur.Format(format)
@@ -1155,9 +1557,17 @@ func ThemeFromHex(bg, grid, taskFill, taskBorder, taskText, text, milestone, tod
 		Milestone:  mustColor(milestone, color.RGBA{0xe6, 0x7e, 0x22, 0xff}),
 		TodayLine:  mustColor(todayLine, color.RGBA{0xd0, 0x02, 0x1b, 0xff}),
 		Vertical:   mustColor(taskBorder, color.RGBA{0x00, 0x7a, 0xcc, 0xff}),
+		Deadline:   color.RGBA{0xc0, 0x39, 0x2b, 0xff},
+		Baseline:   color.RGBA{0xb0, 0xb0, 0xb0, 0xff},
+		Slipped:    color.RGBA{0xe1, 0x70, 0x55, 0xff},
 	}
 }
 
+// ParseColor 解析十六进制颜色，失败时返回 fallback。
+func ParseColor(hex string, fallback color.Color) color.Color {
+	return mustColor(hex, fallback)
+}
+
 func mustColor(hex string, fallback color.Color) color.Color {
 	c, err := parseHexColor(hex)
 	if err != nil {
@@ -0,0 +1,70 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/pyroflux/go-mermaid-gantt/internal/parser"
+)
+
+const (
+	summaryLineHeightPx = 20
+	summaryPaddingPx    = 12
+	summaryFontSize     = 12
+)
+
+// earnedValueLines 将挣值报告格式化为图表下方汇总块的文本行：首行为项目汇总，其后每个 section 一行。
+func earnedValueLines(rep *parser.EarnedValueReport) []string {
+	if rep == nil || len(rep.Tasks) == 0 {
+		return nil
+	}
+	basis := "duration"
+	if rep.CostBased {
+		basis = "cost"
+	}
+	lines := []string{
+		fmt.Sprintf("Earned value as of %s (%s basis)", rep.StatusDate.Format("2006-01-02"), basis),
+		"Project  " + formatEV(rep.Project),
+	}
+	for _, sec := range rep.Sections {
+		if sec.Name == "" || sec.BAC == 0 {
+			continue
+		}
+		lines = append(lines, sec.Name+"  "+formatEV(sec.EVMetrics))
+	}
+	return lines
+}
+
+func formatEV(e parser.EVMetrics) string {
+	return fmt.Sprintf("BAC %.1f  PV %.1f  EV %.1f  SV %+.1f  SPI %.2f", e.BAC, e.PV, e.EV, e.SV, e.SPI)
+}
+
+// summaryHeight 返回汇总块占用的高度，无内容时为 0。
+func summaryHeight(lines []string, scale float64) int {
+	if len(lines) == 0 {
+		return 0
+	}
+	return int(float64(len(lines)*summaryLineHeightPx+summaryPaddingPx*doubleMultiplier) * scale)
+}
+
+// drawSummary 在 top 以下绘制左对齐的汇总块，SPI 低于 1 的项目行使用截止日颜色。
+func drawSummary(img *image.RGBA, lines []string, left, top int, theme ThemeColors, behind bool, fontPath string, scale float64) {
+	if len(lines) == 0 {
+		return
+	}
+	fillRect(img, image.Rect(0, top, img.Bounds().Dx(), img.Bounds().Dy()), theme.Background)
+	fillRect(img, image.Rect(left, top, img.Bounds().Dx(), top+1), theme.Grid)
+	size := int(float64(summaryFontSize) * scale)
+	lineHeight := int(float64(summaryLineHeightPx) * scale)
+	y := top + int(float64(summaryPaddingPx)*scale) + lineHeight/halfDivisor
+	for i, line := range lines {
+		var c color.Color = theme.Text
+		if i == 1 && behind {
+			c = theme.Deadline
+		}
+		width := measureTextWidth(line, c, fontPath, size)
+		drawText(img, c, left+width/halfDivisor, y, line, fontPath, size)
+		y += lineHeight
+	}
+}
@@ -139,3 +139,23 @@ func TestNormalizeMinuteTicksDensity(t *testing.T) {
 		t.Fatalf("expected label step 5 for short span, got %d", label)
 	}
 }
+
+func TestMonthTicksSnapToFirst(t *testing.T) {
+	from := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 12, 20, 0, 0, 0, 0, time.UTC)
+	ticks := monthTicks(from, to, 1)
+	if len(ticks) != 11 {
+		t.Fatalf("expected 11 month ticks, got %d", len(ticks))
+	}
+	for _, tick := range ticks {
+		if tick.Day() != 1 {
+			t.Fatalf("month tick should fall on the 1st, got %s", tick.Format("2006-01-02"))
+		}
+	}
+	if got := monthTicks(from, to, 3); len(got) != 4 || got[1].Month() != time.May {
+		t.Fatalf("expected quarterly ticks starting Feb, got %v", got)
+	}
+	if tickToMonths(parser.TickInterval{Valid: true, Value: 2, Unit: "month"}) != 2 || tickToDays(parser.TickInterval{Valid: true, Value: 2, Unit: "month"}) != 0 {
+		t.Fatalf("month tick interval should be handled as months, not days")
+	}
+}
@@ -6,10 +6,7 @@ import (
 	"github.com/pyroflux/go-mermaid-gantt/internal/parser"
 )
 
-const (
-	daysPerWeek        = 7
-	daysPerMonthApprox = 30
-)
+const daysPerWeek = 7
 
 func tickDuration(t parser.TickInterval) time.Duration {
 	if !t.Valid || t.Value <= 0 {
@@ -28,9 +25,6 @@ func tickDuration(t parser.TickInterval) time.Duration {
 		return time.Duration(t.Value*hoursPerDay) * time.Hour
 	case "week":
 		return time.Duration(t.Value*hoursPerWeek) * time.Hour
-	case "month":
-		// approximate month as 30 days for tick spacing
-		return time.Duration(t.Value*hoursPerMonth) * time.Hour
 	default:
 		return 0
 	}
@@ -56,13 +50,35 @@ func tickToDays(t parser.TickInterval) int {
 		return t.Value
 	case "week":
 		return t.Value * daysPerWeek
-	case "month":
-		return t.Value * daysPerMonthApprox
 	default:
 		return 0
 	}
 }
 
+// tickToMonths 返回月刻度的间隔月数，非月刻度为 0；月刻度按自然月对齐到每月 1 日。
+func tickToMonths(t parser.TickInterval) int {
+	if !t.Valid || t.Value <= 0 || t.Unit != "month" {
+		return 0
+	}
+	return t.Value
+}
+
+// monthTicks 返回 [from, to] 内每隔 step 个月的月初时刻。
+func monthTicks(from, to time.Time, step int) []time.Time {
+	if step <= 0 {
+		step = 1
+	}
+	var out []time.Time
+	cur := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location())
+	if cur.Before(from) {
+		cur = cur.AddDate(0, 1, 0)
+	}
+	for ; !cur.After(to); cur = cur.AddDate(0, step, 0) {
+		out = append(out, cur)
+	}
+	return out
+}
+
 func alignToWeekStart(t time.Time, wd *time.Weekday) time.Time {
 	if wd == nil {
 		return t
@@ -2,6 +2,8 @@ package go_mermaid_gantt
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"image/png"
 	"os"
 	"path/filepath"
@@ -133,3 +135,460 @@ func addDays(date string, days int) string {
 	t, _ := time.Parse("2006-01-02", date)
 	return t.AddDate(0, 0, days).Format("2006-01-02")
 }
+
+func TestRender_DeadlineWarning(t *testing.T) {
+	in := Input{
+		Source: `gantt
+section 发布
+开发 :a1, 2025-03-03, 5d, deadline 2025-03-05
+展会 :m1, after a1, 0d, milestone`,
+		Writer:             &bytes.Buffer{},
+		DisableTodayMarker: true,
+	}
+	res, err := Render(t.Context(), in)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	found := false
+	for _, w := range res.Warnings {
+		if strings.Contains(w, "misses deadline 2025-03-05") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected deadline warning, got %v", res.Warnings)
+	}
+}
+
+func TestRender_RecurringTasks(t *testing.T) {
+	out := filepath.Join(os.TempDir(), "gantt_test_recurring.png")
+	in := Input{
+		Source: `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+section 例会
+发布同步 :sync, 2025-01-06, 1d, every 1w x8
+迭代评审 :milestone, rev, 2025-01-10, 0d, every 2w until 2025-02-28
+section 开发
+功能开发 :dev, 2025-01-06, 20d`,
+		OutputPath:         out,
+		DisableTodayMarker: true,
+	}
+	res, err := Render(t.Context(), in)
+	if err != nil {
+		t.Fatalf("render recurring failed: %v", err)
+	}
+	if len(res.Bytes) == 0 {
+		t.Fatalf("expected png bytes")
+	}
+}
+
+func TestSchedule_CriticalPathAndHighlight(t *testing.T) {
+	src := `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+section 开发
+设计 :d1, 2025-03-03, 3d
+后端 :b1, after d1, 5d
+section 文档
+编写 :w1, after d1, 2d
+section 发布
+上线 :s1, after b1 w1, 1d`
+	plan, err := Schedule(t.Context(), Input{Source: src})
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if got := strings.Join(plan.CriticalPath(), ","); got != "d1,b1,s1" {
+		t.Fatalf("critical path expected d1,b1,s1, got %s", got)
+	}
+	if w1, ok := plan.Task("w1"); !ok || w1.Critical || w1.TotalSlack != 3 {
+		t.Fatalf("w1 should not be critical and have 3 days slack: %+v", w1)
+	}
+
+	out := filepath.Join(os.TempDir(), "gantt_test_critical.png")
+	res, err := Render(t.Context(), Input{Source: src, OutputPath: out, HighlightCritical: true, ShowSlack: true, DisableTodayMarker: true})
+	if err != nil {
+		t.Fatalf("render critical failed: %v", err)
+	}
+	if len(res.Bytes) == 0 {
+		t.Fatalf("expected png bytes")
+	}
+}
+
+func TestSchedule_ResourceOverAllocation(t *testing.T) {
+	plan, err := Schedule(t.Context(), Input{Source: `gantt
+dateFormat YYYY-MM-DD
+resource 前端组 200%
+section 开发
+接口 :a1, 2025-03-03, 3d, alice, 前端组
+页面 :w1, 2025-03-04, 2d, alice, 前端组`})
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if len(plan.OverAllocations) != 1 {
+		t.Fatalf("expected only alice to be over-allocated, got %+v", plan.OverAllocations)
+	}
+	oa := plan.OverAllocations[0]
+	if oa.Resource != "alice" || oa.Start.Format("2006-01-02") != "2025-03-04" || oa.End.Format("2006-01-02") != "2025-03-05" {
+		t.Fatalf("unexpected over-allocation: %+v", oa)
+	}
+	for _, r := range plan.Resources {
+		if r.Name == "前端组" && (r.Capacity != 200 || r.Peak != 200) {
+			t.Fatalf("unexpected team usage: %+v", r)
+		}
+	}
+}
+
+func TestLevel_DelaysNonCriticalWork(t *testing.T) {
+	in := Input{Source: `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+section 后端
+接口 :a1, 2025-03-03, 3d, alice
+联调 :s1, after a1, 2d
+section 前端
+页面 :w1, 2025-03-03, 2d, alice`}
+	before, after, report, err := Level(t.Context(), in)
+	if err != nil {
+		t.Fatalf("level failed: %v", err)
+	}
+	if len(before.OverAllocations) != 1 || len(after.OverAllocations) != 0 {
+		t.Fatalf("expected leveling to clear the conflict: before %v after %v", before.OverAllocations, after.OverAllocations)
+	}
+	if len(report.Moves) != 1 || report.Moves[0].TaskID != "w1" || !report.Moves[0].WithinSlack {
+		t.Fatalf("expected w1 to move within slack, got %+v", report.Moves)
+	}
+	if w1, _ := after.Task("w1"); w1.Start.Format("2006-01-02") != "2025-03-06" {
+		t.Fatalf("w1 expected to start 2025-03-06, got %s", w1.Start.Format("2006-01-02"))
+	}
+
+	in.Writer = &bytes.Buffer{}
+	in.LevelResources = true
+	res, err := Render(t.Context(), in)
+	if err != nil {
+		t.Fatalf("render leveled failed: %v", err)
+	}
+	for _, w := range res.Warnings {
+		if strings.Contains(w, "over-allocated") {
+			t.Fatalf("leveled render should not warn: %v", res.Warnings)
+		}
+	}
+}
+
+func TestRender_ResourceSwimlanes(t *testing.T) {
+	out := filepath.Join(os.TempDir(), "gantt_test_swimlanes.png")
+	in := Input{
+		Source: `gantt
+dateFormat YYYY-MM-DD
+excludes weekends
+section 后端
+接口 :a1, 2025-03-03, 3d, alice
+评审 :r1, after a1, 1d, alice, bob
+section 前端
+页面 :w1, 2025-03-04, 2d, bob
+文档 :d1, 2025-03-05, 1d`,
+		OutputPath:         out,
+		GroupByResource:    true,
+		DisableTodayMarker: true,
+	}
+	res, err := Render(t.Context(), in)
+	if err != nil {
+		t.Fatalf("render swimlanes failed: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(res.Bytes))
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	in.GroupByResource = false
+	in.OutputPath = ""
+	in.Writer = &bytes.Buffer{}
+	plain, err := Render(t.Context(), in)
+	if err != nil {
+		t.Fatalf("render sections failed: %v", err)
+	}
+	plainImg, err := png.Decode(bytes.NewReader(plain.Bytes))
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	// 泳道视图：alice 1 行、bob 1 行（w1 与 r1 不重叠）、未分配 1 行，比按 section 的 4 行更矮
+	if img.Bounds().Dy() >= plainImg.Bounds().Dy() {
+		t.Fatalf("expected compacted swimlanes to be shorter: %d vs %d", img.Bounds().Dy(), plainImg.Bounds().Dy())
+	}
+}
+
+func TestSchedule_HolidayCalendar(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:Christmas\nDTSTART;VALUE=DATE:20241225\nRRULE:FREQ=YEARLY\nEND:VEVENT\nEND:VCALENDAR\n"
+	plan, err := Schedule(context.Background(), Input{
+		Source:          "gantt\nexcludes weekends\nsection A\nShip :a1, 2025-12-24, 2d\n",
+		HolidayCalendar: strings.NewReader(ics),
+	})
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	task, _ := plan.Task("a1")
+	if got := task.End.Format("2006-01-02"); got != "2025-12-26" {
+		t.Fatalf("expected 2025-12-25 to be skipped, got end %s", got)
+	}
+}
+
+func TestSchedule_HolidaysDirective(t *testing.T) {
+	plan, err := Schedule(context.Background(), Input{
+		Source: "gantt\nexcludes weekends\nholidays US 2025\nsection A\nShip :a1, 2025-11-26, 2d\n",
+	})
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	task, _ := plan.Task("a1")
+	if got := task.End.Format("2006-01-02"); got != "2025-11-28" {
+		t.Fatalf("expected Thanksgiving to be skipped, got end %s", got)
+	}
+	if len(plan.Holidays) != 1 || plan.Holidays[0].Name != "Thanksgiving Day" {
+		t.Fatalf("expected Thanksgiving in plan holidays, got %+v", plan.Holidays)
+	}
+}
+
+func TestSchedule_FromEnd(t *testing.T) {
+	plan, err := Schedule(context.Background(), Input{
+		Source: "gantt\nexcludes weekends\nscheduleFrom end 2025-06-30\nsection A\nBuild :b1, 5d\nTest :t1, after b1, 3d\n",
+	})
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	// 06-30 为周一：t1 06-26..06-30，b1 06-19..06-25
+	if got := plan.ProjectStart.Format("2006-01-02"); got != "2025-06-19" {
+		t.Fatalf("expected latest project start 2025-06-19, got %s", got)
+	}
+	if got := plan.ProjectEnd.Format("2006-01-02"); got != "2025-06-30" {
+		t.Fatalf("expected project end 2025-06-30, got %s", got)
+	}
+	task, _ := plan.Task("t1")
+	if got := task.End.Format("2006-01-02"); got != "2025-06-30" || task.TotalSlack != 0 {
+		t.Fatalf("expected t1 to end on the project end without slack, got %s slack %d", got, task.TotalSlack)
+	}
+}
+
+func TestSimulate_MilestoneForecast(t *testing.T) {
+	src := "gantt\ndateFormat YYYY-MM-DD\nexcludes weekends\nsection A\nBuild :b1, 2025-03-03, 3d/5d/10d\nShip :milestone, m1, after b1, 0d\n"
+	opt := SimulationOptions{Runs: 200, Seed: 42}
+	fc, err := Simulate(t.Context(), Input{Source: src}, opt)
+	if err != nil {
+		t.Fatalf("simulate failed: %v", err)
+	}
+	if fc.Runs != 200 || len(fc.Milestones) != 1 {
+		t.Fatalf("unexpected forecast: %+v", fc)
+	}
+	m1 := fc.Milestones[0]
+	if m1.P50.IsZero() || m1.P95.Before(m1.P50) || !fc.Finish.P95.After(fc.Finish.Planned) {
+		t.Fatalf("unexpected percentiles: %+v / %+v", m1, fc.Finish)
+	}
+
+	res, err := Render(t.Context(), Input{Source: src, Writer: &bytes.Buffer{}, ForecastBand: true, Simulation: opt, DisableTodayMarker: true})
+	if err != nil {
+		t.Fatalf("render with forecast band failed: %v", err)
+	}
+	if len(res.Bytes) == 0 {
+		t.Fatalf("expected png bytes")
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	opt.Runs = 1_000_000
+	if _, err := Render(ctx, Input{Source: src, Writer: &bytes.Buffer{}, ForecastBand: true, Simulation: opt}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancelled forecast render to stop, got %v", err)
+	}
+}
+
+func TestEarnedValue_ProjectSummary(t *testing.T) {
+	in := Input{
+		Source: "gantt\ndateFormat YYYY-MM-DD\nexcludes weekends\nsection A\nDesign :d1, 2025-03-03, 5d, done\nBuild :b1, after d1, 10d, 20%\n",
+		Today:  "2025-03-14",
+	}
+	rep, err := EarnedValue(t.Context(), in)
+	if err != nil {
+		t.Fatalf("earned value failed: %v", err)
+	}
+	if rep.StatusDate.Format("2006-01-02") != "2025-03-14" || rep.CostBased {
+		t.Fatalf("unexpected status or basis: %+v", rep)
+	}
+	if p := rep.Project; p.PV != 10 || p.EV != 7 || p.SV != -3 {
+		t.Fatalf("unexpected project metrics: %+v", p)
+	}
+
+	in.Writer = &bytes.Buffer{}
+	in.ShowEarnedValue = true
+	with, err := Render(t.Context(), in)
+	if err != nil {
+		t.Fatalf("render with earned value failed: %v", err)
+	}
+	in.ShowEarnedValue = false
+	without, err := Render(t.Context(), in)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	a, _ := png.Decode(bytes.NewReader(with.Bytes))
+	b, _ := png.Decode(bytes.NewReader(without.Bytes))
+	if a.Bounds().Dy() <= b.Bounds().Dy() {
+		t.Fatalf("expected summary block to add height: %d vs %d", a.Bounds().Dy(), b.Bounds().Dy())
+	}
+}
+
+func TestRender_BaselineVariances(t *testing.T) {
+	baseline := "gantt\ndateFormat YYYY-MM-DD\nexcludes weekends\nsection A\nDesign :d1, 2025-03-03, 3d\nBuild :b1, after d1, 5d\n"
+	current := "gantt\ndateFormat YYYY-MM-DD\nexcludes weekends\nsection A\nDesign :d1, 2025-03-03, 4d\nBuild :b1, after d1, 5d\n"
+	res, err := Render(t.Context(), Input{Source: current, Baseline: baseline, Writer: &bytes.Buffer{}, DisableTodayMarker: true})
+	if err != nil {
+		t.Fatalf("render with baseline failed: %v", err)
+	}
+	if len(res.Variances) != 2 {
+		t.Fatalf("expected 2 variances, got %+v", res.Variances)
+	}
+	if b1 := res.Variances[1]; b1.TaskID != "b1" || b1.StartDays != 1 || b1.FinishDays != 1 || !b1.Slipped {
+		t.Fatalf("unexpected b1 variance: %+v", b1)
+	}
+
+	// 保存的排程结果同样可作为基线
+	frozen, err := Schedule(t.Context(), Input{Source: baseline})
+	if err != nil {
+		t.Fatalf("schedule baseline failed: %v", err)
+	}
+	plan, err := Schedule(t.Context(), Input{Source: current, BaselinePlan: &frozen})
+	if err != nil {
+		t.Fatalf("schedule with baseline failed: %v", err)
+	}
+	if len(plan.Variances) != 2 || plan.Variances[0].FinishDays != 1 || plan.Variances[0].StartDays != 0 {
+		t.Fatalf("unexpected plan variances: %+v", plan.Variances)
+	}
+}
+
+func TestSchedule_Reforecast(t *testing.T) {
+	src := "gantt\ndateFormat YYYY-MM-DD\nexcludes weekends\nsection A\nBuild :b1, 2025-03-03, 10d, active, 30%\nShip :milestone, m1, after b1, 0d\n"
+	plan, err := Schedule(t.Context(), Input{Source: src, Today: "2025-03-17", Reforecast: true})
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	// 计划 03-14 完成，实际 30%：剩余 7d 自 03-17 起，至 03-25
+	b1, _ := plan.Task("b1")
+	if !b1.Reforecast || b1.PlannedEnd.Format("2006-01-02") != "2025-03-14" || b1.End.Format("2006-01-02") != "2025-03-25" {
+		t.Fatalf("unexpected reforecast: %+v", b1)
+	}
+	if m1, _ := plan.Task("m1"); m1.Start.Format("2006-01-02") != "2025-03-26" {
+		t.Fatalf("expected milestone to follow the forecast, got %s", m1.Start)
+	}
+	if _, err := Render(t.Context(), Input{Source: src, Today: "2025-03-17", Reforecast: true, Writer: &bytes.Buffer{}}); err != nil {
+		t.Fatalf("render reforecast failed: %v", err)
+	}
+
+	// 固定起止的任务同样重排：保留开始，结束顺延到预测完成
+	fixed := strings.Replace(src, "2025-03-03, 10d", "2025-03-03, 2025-03-14", 1)
+	plan, err = Schedule(t.Context(), Input{Source: fixed, Today: "2025-03-17", Reforecast: true})
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if b1, _ := plan.Task("b1"); !b1.Reforecast || b1.Start.Format("2006-01-02") != "2025-03-03" || b1.End.Format("2006-01-02") != "2025-03-25" {
+		t.Fatalf("unexpected fixed-date reforecast: %+v", b1)
+	}
+	if m1, _ := plan.Task("m1"); m1.Start.Format("2006-01-02") != "2025-03-26" {
+		t.Fatalf("expected milestone to follow the fixed-date forecast, got %s", m1.Start)
+	}
+}
+
+func TestSchedule_TodayInTimezone(t *testing.T) {
+	// Input.Today 按图表时区解析，UTC 以西的时区下 today 与 start of month 不应提前一天
+	src := "gantt\ndateFormat YYYY-MM-DD\nsection A\nNow :a1, today, 1d\nMonth :b1, start of month, 1d\n"
+	for _, in := range []Input{
+		{Source: "gantt\ntimezone America/New_York\n" + strings.TrimPrefix(src, "gantt\n"), Today: "2025-03-01"},
+		{Source: src, Today: "2025-03-01", Timezone: "America/Los_Angeles"},
+	} {
+		plan, err := Schedule(t.Context(), in)
+		if err != nil {
+			t.Fatalf("schedule failed: %v", err)
+		}
+		for _, id := range []string{"a1", "b1"} {
+			if task, _ := plan.Task(id); task.Start.Format("2006-01-02") != "2025-03-01" {
+				t.Fatalf("%s: expected start 2025-03-01, got %s", id, task.Start)
+			}
+		}
+	}
+}
+
+func TestSchedule_SectionSummary(t *testing.T) {
+	src := "gantt\ndateFormat YYYY-MM-DD\nexcludes weekends\nsection Build\nDesign :d1, 2025-03-03, 5d, done\nCode :c1, after d1, 10d, 20%\nsection Release\nShip :milestone, s1, after c1, 0d\n"
+	plan, err := Schedule(t.Context(), Input{Source: src})
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if len(plan.Sections) != 2 {
+		t.Fatalf("expected 2 section summaries, got %+v", plan.Sections)
+	}
+	b := plan.Sections[0]
+	if b.Name != "Build" || b.Tasks != 2 || b.Done != 1 || b.Progress != 47 || b.End.Format("2006-01-02") != "2025-03-21" {
+		t.Fatalf("unexpected build summary: %+v", b)
+	}
+	if _, err := Render(t.Context(), Input{Source: src, ShowSectionSummary: true, Writer: &bytes.Buffer{}}); err != nil {
+		t.Fatalf("render section summary failed: %v", err)
+	}
+}
+
+func TestSchedule_NestedSections(t *testing.T) {
+	src := "gantt\ndateFormat YYYY-MM-DD\nsection Program / Backend / API\nAuth :a1, 2025-03-03, 3d\nsection Program / Backend / Storage\nSchema :s1, 2025-03-03, 5d\nsection Program / Release\nShip :r1, after Program/Backend, 1d\n"
+	plan, err := Schedule(t.Context(), Input{Source: src})
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if len(plan.Sections) != 5 {
+		t.Fatalf("expected 5 sections including groups, got %+v", plan.Sections)
+	}
+	backend := plan.Sections[1]
+	if backend.Name != "Program / Backend" || backend.Parent != "Program" || backend.Level != 1 || backend.Tasks != 2 {
+		t.Fatalf("unexpected backend summary: %+v", backend)
+	}
+	if r1, _ := plan.Task("r1"); r1.Section != "Program / Release" || r1.Start.Format("2006-01-02") != "2025-03-08" {
+		t.Fatalf("unexpected r1: %+v", r1)
+	}
+	if _, err := Render(t.Context(), Input{Source: src, ShowSectionSummary: true, Writer: &bytes.Buffer{}}); err != nil {
+		t.Fatalf("render nested sections failed: %v", err)
+	}
+}
+
+func TestSchedule_DependencyDiagnostics(t *testing.T) {
+	src := "gantt\ndateFormat YYYY-MM-DD\nsection S\nDesign :design, 2025-03-03, 2d\nCode :code, after desgin, 3d\nTest :test, after qa, 2d\n"
+	_, err := Schedule(t.Context(), Input{Source: src})
+	if err == nil || !strings.Contains(err.Error(), "line 5: dependency not found: desgin (did you mean design?)") ||
+		!strings.Contains(err.Error(), "line 6: dependency not found: qa") {
+		t.Fatalf("expected every missing dependency to be reported, got %v", err)
+	}
+
+	dup := "gantt\ndateFormat YYYY-MM-DD\nsection S\nA :a1, 2025-03-03, 1d\nB :a1, 2025-03-04, 1d\n"
+	plan, err := Schedule(t.Context(), Input{Source: dup})
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if len(plan.Warnings) != 1 || !strings.Contains(plan.Warnings[0], "renamed to a1_1") {
+		t.Fatalf("expected duplicate id warning, got %v", plan.Warnings)
+	}
+}
+
+func TestSchedule_EffortAssignments(t *testing.T) {
+	src := "gantt\ndateFormat YYYY-MM-DD\nexcludes weekends\nsection Build\nSolo :e1, 2025-03-03, effort 10d, alice\nPair :e2, after e1, effort 10d, alice, bob\n"
+	plan, err := Schedule(t.Context(), Input{Source: src})
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	e1, _ := plan.Task("e1")
+	e2, _ := plan.Task("e2")
+	if e1.Effort != "10d" || e1.Duration != "10d" || e2.Duration != "5d" {
+		t.Fatalf("doubling staff should halve the duration: e1=%+v e2=%+v", e1, e2)
+	}
+	if len(e2.Assignments) != 2 || e2.Assignments[1] != (Assignment{Resource: "bob", Units: 100}) {
+		t.Fatalf("unexpected assignments: %+v", e2.Assignments)
+	}
+
+	half := strings.Replace(src, "alice, bob", "alice 50%, bob 50%", 1)
+	plan, err = Schedule(t.Context(), Input{Source: half})
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if e2, _ := plan.Task("e2"); e2.Duration != "10d" || e2.Assignments[0].Units != 50 {
+		t.Fatalf("two half-time resources should take the full effort: %+v", e2)
+	}
+}
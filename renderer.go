@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -24,41 +25,28 @@ func (rendererImpl) Render(ctx context.Context, in Input) (RenderResult, error)
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	if in.Source == "" {
-		return RenderResult{}, fmt.Errorf("source is empty")
-	}
 	if in.OutputPath == "" && in.Writer == nil {
 		return RenderResult{}, fmt.Errorf("output target missing (OutputPath or Writer)")
 	}
 
-	var model parser.Model
-	var err error
-	if in.FromFile {
-		if _, statErr := os.Stat(in.Source); statErr != nil {
-			return RenderResult{}, fmt.Errorf("source file: %w", statErr)
-		}
-		model, err = parser.ParseFile(in.Source)
-	} else {
-		model, err = parser.Parse(in.Source)
-	}
+	model, err := parseInput(in)
 	if err != nil {
 		return RenderResult{}, err
 	}
-	if in.Timezone != "" {
-		model.Calendar.Timezone = in.Timezone
+	var forecast parser.Forecast
+	if in.ForecastBand {
+		// 模拟需要未排程的模型，须在 resolveInput 之前执行
+		if forecast, err = parser.Simulate(ctx, model, simulationOptions(in, in.Simulation)); err != nil {
+			return RenderResult{}, err
+		}
 	}
-	if in.DisableTodayMarker {
-		model.Today.Enabled = false
+	if model, err = resolveInput(model, in); err != nil {
+		return RenderResult{}, err
 	}
-	if in.Today != "" {
-		if t, err := time.Parse(parserDateLayout(model.DateFormat), in.Today); err == nil {
-			model.Today.Enabled = true
-			model.Today.HasDate = true
-			model.Today.Date = t
-		}
+	if in.ForecastBand {
+		parser.ApplyForecast(&model, forecast)
 	}
-
-	model, err = parser.ResolveSchedule(model)
+	variances, err := applyBaseline(&model, in)
 	if err != nil {
 		return RenderResult{}, err
 	}
@@ -74,6 +62,9 @@ func (rendererImpl) Render(ctx context.Context, in Input) (RenderResult, error)
 		theme.Milestone,
 		theme.TodayLine,
 	)
+	colors.Deadline = render.ParseColor(theme.Deadline, colors.Deadline)
+	colors.Baseline = render.ParseColor(theme.Baseline, colors.Baseline)
+	colors.Slipped = render.ParseColor(theme.Slipped, colors.Slipped)
 
 	fontPath, fontErr := font.SelectFontPath(in.FontPath)
 	if fontErr != nil {
@@ -100,6 +91,15 @@ func (rendererImpl) Render(ctx context.Context, in Input) (RenderResult, error)
 		FontPath: fontPath,
 		Calendar: model.Calendar,
 		Today:    model.Today,
+
+		HighlightCritical: in.HighlightCritical,
+		ShowSlack:         in.ShowSlack,
+		GroupByResource:   in.GroupByResource,
+		SectionSummary:    in.ShowSectionSummary,
+	}
+	if in.ShowEarnedValue {
+		ev := parser.EarnedValue(model, parser.StatusDate(model))
+		opt.EarnedValue = &ev
 	}
 
 	imgBytes, err := render.RenderModel(ctx, model, opt)
@@ -107,7 +107,10 @@ func (rendererImpl) Render(ctx context.Context, in Input) (RenderResult, error)
 		return RenderResult{}, err
 	}
 
-	res := RenderResult{Bytes: imgBytes}
+	for _, w := range model.Warnings {
+		warnings = append(warnings, w.String())
+	}
+	res := RenderResult{Bytes: imgBytes, Variances: variances}
 	if len(warnings) > 0 {
 		res.Warnings = append(res.Warnings, warnings...)
 	}
@@ -126,6 +129,77 @@ func (rendererImpl) Render(ctx context.Context, in Input) (RenderResult, error)
 	return res, nil
 }
 
+// loadModel 解析输入并完成排程（按需执行资源平衡），Render 与 Schedule 共用。
+func loadModel(in Input) (parser.Model, error) {
+	model, err := parseInput(in)
+	if err != nil {
+		return parser.Model{}, err
+	}
+	return resolveInput(model, in)
+}
+
+// resolveInput 对已解析的模型排程，LevelResources 时执行资源平衡。
+func resolveInput(model parser.Model, in Input) (parser.Model, error) {
+	if in.LevelResources {
+		leveled, _, err := parser.LevelResources(model)
+		return leveled, err
+	}
+	return parser.ResolveSchedule(model)
+}
+
+// parseInput 解析源并应用 Input 中的时区与今日设置，尚未排程。
+func parseInput(in Input) (parser.Model, error) {
+	if in.Source == "" {
+		return parser.Model{}, fmt.Errorf("source is empty")
+	}
+	var model parser.Model
+	var err error
+	opts := parser.ParseOptions{FS: in.CalendarFS, AllowLocalFiles: in.AllowLocalCalendar}
+	if in.FromFile {
+		if _, statErr := os.Stat(in.Source); statErr != nil {
+			return parser.Model{}, fmt.Errorf("source file: %w", statErr)
+		}
+		data, readErr := os.ReadFile(in.Source)
+		if readErr != nil {
+			return parser.Model{}, fmt.Errorf("read source file: %w", readErr)
+		}
+		opts.BaseDir = filepath.Dir(in.Source)
+		model, err = parser.ParseWithOptions(string(data), opts)
+	} else {
+		model, err = parser.ParseWithOptions(in.Source, opts)
+	}
+	if err != nil {
+		return parser.Model{}, err
+	}
+	if in.HolidayCalendar != nil {
+		if err := parser.ApplyICS(&model, in.HolidayCalendar, in.HolidayWorkdays); err != nil {
+			return parser.Model{}, fmt.Errorf("holiday calendar: %w", err)
+		}
+	}
+	if in.Timezone != "" {
+		model.Calendar.Timezone = in.Timezone
+	}
+	if in.DisableTodayMarker {
+		model.Today.Enabled = false
+	}
+	model.Reforecast = in.Reforecast
+	if in.Today != "" {
+		loc := time.UTC
+		if tz, err := time.LoadLocation(model.Calendar.Timezone); err == nil {
+			loc = tz
+		}
+		// 按图表时区解析，避免 UTC 以西的时区把今日换算到前一天
+		if t, err := time.ParseInLocation(parserDateLayout(model.DateFormat), in.Today, loc); err == nil {
+			// 固定渲染时钟，相对日期表达式随之可复现
+			model.Clock = t
+			model.Today.Enabled = true
+			model.Today.HasDate = true
+			model.Today.Date = t
+		}
+	}
+	return model, nil
+}
+
 // Errors 定义
 var (
 	ErrInvalidInput = errors.New("invalid input")
@@ -0,0 +1,296 @@
+package go_mermaid_gantt
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pyroflux/go-mermaid-gantt/calendar"
+	"github.com/pyroflux/go-mermaid-gantt/internal/parser"
+)
+
+// ScheduledTask 描述排程后的单个任务。
+type ScheduledTask struct {
+	ID        string
+	Name      string
+	Section   string
+	Start     time.Time // 最早开始（即排程结果）
+	End       time.Time // 最早完成
+	Milestone bool
+	Critical  bool     // 位于关键路径：延误将推迟项目完成或截止日
+	DependsOn []string // 依赖的任务 ID（按源中顺序）
+	Resources []string
+
+	Duration    string       // 排程所用工期（mermaid 语法，如 7d）；effort 任务为按资源占用推算的结果
+	Effort      string       // effort 工作量，未指定时为空
+	Assignments []Assignment // 各资源的占用，与 Resources 顺序一致
+
+	LateStart  time.Time // 不推迟项目完成的最迟开始
+	LateFinish time.Time // 不推迟项目完成的最迟完成
+	TotalSlack int       // 总时差（工作日）
+	FreeSlack  int       // 自由时差（工作日）：不推迟任何后继
+
+	Reforecast   bool      // Input.Reforecast 下因进度落后被重排，End 为预测完成
+	PlannedStart time.Time // 重排前的计划开始
+	PlannedEnd   time.Time // 重排前的计划完成
+}
+
+// SectionSummary 为 section 的汇总：任务最早开始到最晚完成及按工期加权的进度；分组包含其下级 section。
+type SectionSummary struct {
+	Name     string // 完整名称，嵌套 section 为 "Backend / API"
+	Parent   string // 上级分组的完整名称，顶层为空
+	Level    int    // 嵌套深度，顶层为 0
+	Start    time.Time
+	End      time.Time
+	Progress int // 0-100，按工作日工期加权；全部为里程碑时按任务数平均
+	Tasks    int // 任务数
+	Done     int // 已完成（done 或 100%）的任务数
+}
+
+// Assignment 为任务对单个资源的占用。
+type Assignment struct {
+	Resource string
+	Units    int // 占用百分比，`alice 50%` 标注，缺省 100
+}
+
+// DailyLoad 为资源在某个工作日的负荷。
+type DailyLoad struct {
+	Date    time.Time
+	Load    int      // 当日峰值并发占用（百分比）
+	TaskIDs []string // 当日承担的任务
+}
+
+// ResourceUsage 汇总单个资源的容量与每日负荷。
+type ResourceUsage struct {
+	Name     string
+	Capacity int // 容量百分比，`resource <name> <N>%` 声明，缺省 100
+	Peak     int // 最高单日负荷
+	Days     []DailyLoad
+}
+
+// OverAllocation 描述资源在连续日期内超出容量。
+type OverAllocation struct {
+	Resource string
+	Start    time.Time // 首个过载日
+	End      time.Time // 最后一个过载日（含）
+	TaskIDs  []string  // 冲突任务
+	Message  string
+}
+
+// Holiday 为带名称的假日或调休上班日（来自 holidays 指令或 ics 日历）。
+type Holiday struct {
+	Date    time.Time
+	Name    string
+	Workday bool // 调休上班日
+}
+
+// Plan 为排程结果，不涉及绘制。
+type Plan struct {
+	Tasks           []ScheduledTask
+	Sections        []SectionSummary // 按源中顺序的 section 汇总
+	Resources       []ResourceUsage
+	OverAllocations []OverAllocation
+	Holidays        []Holiday         // 落在项目起止范围内的假日
+	Calendar        calendar.Calendar // 排程所用日历，可按与图表相同的规则推算日期
+	ProjectStart    time.Time         // 最早任务的开始；scheduleFrom end 时即倒排得到的项目最迟开始
+	ProjectEnd      time.Time         // scheduleFrom end 指定的项目完成日，前推排程时为零值
+	Variances       []Variance        // 指定基线（Input.Baseline/BaselinePlan）时各任务的起止偏差
+	Warnings        []string
+}
+
+// Schedule 解析并排程 Input.Source，返回任务起止与关键路径等信息；输出相关字段被忽略。
+func Schedule(ctx context.Context, in Input) (Plan, error) {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return Plan{}, err
+		}
+	}
+	model, err := loadModel(in)
+	if err != nil {
+		return Plan{}, err
+	}
+	variances, err := applyBaseline(&model, in)
+	if err != nil {
+		return Plan{}, err
+	}
+	plan := planFromModel(model)
+	plan.Variances = variances
+	return plan, nil
+}
+
+// TaskMove 描述资源平衡后开始时间变化的任务。
+type TaskMove struct {
+	TaskID      string
+	From        time.Time // 平衡前开始
+	To          time.Time // 平衡后开始
+	Days        int       // 推迟的工作日数
+	Direct      bool      // 被平衡直接推迟；false 表示随前驱顺延
+	WithinSlack bool      // 推迟未超过原总时差，项目完成不受影响
+}
+
+// LevelingReport 为资源平衡报告。
+type LevelingReport struct {
+	Moves      []TaskMove
+	Unresolved []OverAllocation // 无法通过推迟消除的过载（如冲突任务均为固定日期）
+}
+
+// Level 对 Input.Source 执行资源平衡，返回平衡前、后的排程与调整报告，
+// 便于并排展示 "unleveled" 与 "leveled" 两个版本。
+func Level(ctx context.Context, in Input) (before Plan, after Plan, report LevelingReport, err error) {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return Plan{}, Plan{}, LevelingReport{}, err
+		}
+	}
+	model, err := parseInput(in)
+	if err != nil {
+		return Plan{}, Plan{}, LevelingReport{}, err
+	}
+	leveled, rep, err := parser.LevelResources(model)
+	if err != nil {
+		return Plan{}, Plan{}, LevelingReport{}, err
+	}
+	unleveled, err := parser.ResolveSchedule(model)
+	if err != nil {
+		return Plan{}, Plan{}, LevelingReport{}, err
+	}
+	for _, mv := range rep.Moves {
+		report.Moves = append(report.Moves, TaskMove(mv))
+	}
+	for _, w := range rep.Unresolved {
+		report.Unresolved = append(report.Unresolved, overAllocation(w))
+	}
+	return planFromModel(unleveled), planFromModel(leveled), report, nil
+}
+
+func overAllocation(w parser.Warning) OverAllocation {
+	return OverAllocation{
+		Resource: w.Resource,
+		Start:    w.Date,
+		End:      w.End,
+		TaskIDs:  w.TaskIDs,
+		Message:  w.Message,
+	}
+}
+
+func planFromModel(m parser.Model) Plan {
+	plan := Plan{Calendar: m.Calendar, ProjectStart: m.ProjectStart}
+	if m.ScheduleFromEnd {
+		plan.ProjectEnd = m.ProjectEnd
+	}
+	for _, sec := range m.Sections {
+		for _, t := range sec.Tasks {
+			if t.IsVertical {
+				continue
+			}
+			st := ScheduledTask{
+				ID:        t.ID,
+				Name:      t.Name,
+				Section:   sec.Name,
+				Start:     t.Start,
+				End:       t.End,
+				Milestone: t.IsMilestone || t.Duration.Value == 0,
+				Critical:  t.Critical,
+
+				LateStart:  t.LateStart,
+				LateFinish: t.LateFinish,
+				TotalSlack: t.TotalSlack,
+				FreeSlack:  t.FreeSlack,
+
+				Reforecast:   t.Reforecast,
+				PlannedStart: t.PlannedStart,
+				PlannedEnd:   t.PlannedEnd,
+			}
+			for _, dep := range t.Dependencies {
+				st.DependsOn = append(st.DependsOn, dep.Target)
+			}
+			st.Resources = append(st.Resources, t.Resources...)
+			for _, r := range t.Resources {
+				st.Assignments = append(st.Assignments, Assignment{Resource: r, Units: t.UnitsFor(r)})
+			}
+			if !st.Milestone {
+				st.Duration = t.Duration.String()
+			}
+			if t.Effort.Value > 0 {
+				st.Effort = t.Effort.String()
+			}
+			plan.Tasks = append(plan.Tasks, st)
+		}
+	}
+	for _, r := range parser.RollupSections(m) {
+		plan.Sections = append(plan.Sections, SectionSummary(r))
+	}
+	for _, r := range m.Resources {
+		usage := ResourceUsage{Name: r.Name, Capacity: r.Capacity}
+		for _, l := range m.Loads {
+			if l.Resource != r.Name {
+				continue
+			}
+			usage.Days = append(usage.Days, DailyLoad{Date: l.Date, Load: l.Load, TaskIDs: l.TaskIDs})
+			if l.Load > usage.Peak {
+				usage.Peak = l.Load
+			}
+		}
+		plan.Resources = append(plan.Resources, usage)
+	}
+	plan.Holidays = holidaysInSpan(m.Calendar.Holidays, plan.Tasks)
+	for _, w := range m.Warnings {
+		if w.Kind == parser.WarningOverAllocated {
+			plan.OverAllocations = append(plan.OverAllocations, overAllocation(w))
+		}
+		plan.Warnings = append(plan.Warnings, w.String())
+	}
+	return plan
+}
+
+// holidaysInSpan 筛选落在任务最早开始日到最晚结束日之间的假日。
+func holidaysInSpan(list []parser.Holiday, tasks []ScheduledTask) []Holiday {
+	if len(tasks) == 0 {
+		return nil
+	}
+	first, last := tasks[0].Start, tasks[0].End
+	for _, t := range tasks[1:] {
+		if t.Start.Before(first) {
+			first = t.Start
+		}
+		if t.End.After(last) {
+			last = t.End
+		}
+	}
+	from := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, time.UTC)
+	var out []Holiday
+	for _, h := range list {
+		d := time.Date(h.Date.Year(), h.Date.Month(), h.Date.Day(), 0, 0, 0, 0, time.UTC)
+		if !d.Before(from) && !d.After(to) {
+			out = append(out, Holiday(h))
+		}
+	}
+	return out
+}
+
+// CriticalPath 返回关键任务 ID，按开始时间排序。
+func (p Plan) CriticalPath() []string {
+	crit := make([]ScheduledTask, 0, len(p.Tasks))
+	for _, t := range p.Tasks {
+		if t.Critical {
+			crit = append(crit, t)
+		}
+	}
+	sort.SliceStable(crit, func(i, j int) bool { return crit[i].Start.Before(crit[j].Start) })
+	ids := make([]string, len(crit))
+	for i, t := range crit {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+// Task 按 ID 查找排程后的任务。
+func (p Plan) Task(id string) (ScheduledTask, bool) {
+	for _, t := range p.Tasks {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return ScheduledTask{}, false
+}
@@ -12,6 +12,9 @@ type Theme struct {
 	Milestone  string // 里程碑标记颜色
 	TodayLine  string // 今日基准线颜色
 	Vertical   string // 垂直标记（vert）颜色
+	Deadline   string // 截止日标记与逾期任务颜色
+	Baseline   string // 基线细条颜色
+	Slipped    string // 完成晚于基线的任务颜色
 }
 
 func DefaultTheme() Theme {
@@ -26,6 +29,9 @@ func DefaultTheme() Theme {
 		Milestone:  "#e67e22",
 		TodayLine:  "#d0021b",
 		Vertical:   "#007acc",
+		Deadline:   "#c0392b",
+		Baseline:   "#b0b0b0",
+		Slipped:    "#e17055",
 	}
 }
 
@@ -41,6 +47,9 @@ func DarkTheme() Theme {
 		Milestone:  "#f1c40f",
 		TodayLine:  "#e74c3c",
 		Vertical:   "#29b6f6",
+		Deadline:   "#ff6b6b",
+		Baseline:   "#6e6e73",
+		Slipped:    "#ff8a65",
 	}
 }
 
@@ -77,5 +86,14 @@ func MergeTheme(base, override Theme) Theme {
 	if override.Vertical != "" {
 		out.Vertical = override.Vertical
 	}
+	if override.Deadline != "" {
+		out.Deadline = override.Deadline
+	}
+	if override.Baseline != "" {
+		out.Baseline = override.Baseline
+	}
+	if override.Slipped != "" {
+		out.Slipped = override.Slipped
+	}
 	return out
 }
@@ -3,22 +3,38 @@ package go_mermaid_gantt
 import (
 	"context"
 	"io"
+	"io/fs"
 )
 
 // Input 描述渲染所需的输入参数。
 type Input struct {
-	Source             string    // Mermaid Gantt 源（文本或文件路径）
-	FromFile           bool      // 是否将 Source 视为文件路径
-	Theme              Theme     // 主题配置，未设置则使用默认
-	OutputPath         string    // 输出 PNG 路径（可选，与 Writer 至少一个）
-	Writer             io.Writer // 输出目标 Writer（可选）
-	Width              int       // 图像宽度，0 表示使用默认
-	Height             int       // 图像高度，0 表示使用默认
-	Scale              float64   // 缩放倍数，0 表示默认 1.0
-	FontPath           string    // 自定义字体路径（支持中文），为空则使用内置/系统字体
-	Timezone           string    // 时间计算使用的时区，空则使用 UTC
-	Today              string    // 覆盖今日标记日期（YYYY-MM-DD），空则使用当前日期
-	DisableTodayMarker bool      // 是否禁用今日标记
+	Source             string            // Mermaid Gantt 源（文本或文件路径）
+	FromFile           bool              // 是否将 Source 视为文件路径
+	Theme              Theme             // 主题配置，未设置则使用默认
+	OutputPath         string            // 输出 PNG 路径（可选，与 Writer 至少一个）
+	Writer             io.Writer         // 输出目标 Writer（可选）
+	Width              int               // 图像宽度，0 表示使用默认
+	Height             int               // 图像高度，0 表示使用默认
+	Scale              float64           // 缩放倍数，0 表示默认 1.0
+	FontPath           string            // 自定义字体路径（支持中文），为空则使用内置/系统字体
+	Timezone           string            // 时间计算使用的时区，空则使用 UTC
+	Today              string            // 覆盖今日标记日期（YYYY-MM-DD），空则使用当前日期
+	DisableTodayMarker bool              // 是否禁用今日标记
+	HighlightCritical  bool              // 自动以 crit 配色高亮关键路径并强调其依赖连线
+	ShowSlack          bool              // 在任务条后绘制总时差细线（至最迟完成）
+	LevelResources     bool              // 排程后执行资源平衡，推迟任务消除资源过载
+	GroupByResource    bool              // 资源泳道视图：每个资源一条泳道，不重叠的任务共享一行
+	CalendarFS         fs.FS             // excludes ics 引用的日历文件在此文件系统内查找
+	AllowLocalCalendar bool              // CalendarFS 为 nil 时允许 excludes ics 读取本地文件；FromFile 时限于源文件目录之下
+	HolidayCalendar    io.Reader         // 额外的 RFC 5545 日历，全天事件作为排除日期
+	HolidayWorkdays    bool              // HolidayCalendar 中标记为上班日的事件作为 includes
+	ForecastBand       bool              // 对三点估算做蒙特卡洛模拟，在里程碑后绘制 P50–P90 阴影带
+	Simulation         SimulationOptions // ForecastBand 使用的模拟参数
+	ShowEarnedValue    bool              // 在图表下方绘制截至今日的挣值汇总（PV/EV/SV/SPI）
+	Baseline           string            // 基线源（Mermaid 文本），按任务 ID 在实际任务条下方绘制基线细条
+	BaselinePlan       *Plan             // 已排程的基线（如保存的 Schedule 结果），优先于 Baseline
+	ShowSectionSummary bool              // 在 section 标题行绘制覆盖其任务起止的汇总条与按工期加权的汇总进度
+	Reforecast         bool              // 以今日为状态日，将落后于进度的剩余工作从今日起重排，后继随之顺延
 }
 
 // RenderResult 返回渲染结果。
@@ -26,6 +42,7 @@ type RenderResult struct {
 	OutputPath string
 	Bytes      []byte
 	Warnings   []string
+	Variances  []Variance // 指定基线时各任务的起止偏差
 }
 
 // Renderer 定义渲染器接口，便于后续替换实现。